@@ -0,0 +1,234 @@
+package powkit
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/sencha-dev/powkit/autolykos2"
+	"github.com/sencha-dev/powkit/cuckoo"
+	"github.com/sencha-dev/powkit/eaglesong"
+	"github.com/sencha-dev/powkit/heavyhash"
+	"github.com/sencha-dev/powkit/octopus"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+type octopusHasher struct {
+	client *octopus.Client
+}
+
+func (h *octopusHasher) Compute(hash []byte, height, nonce uint64) ([]byte, []byte, error) {
+	digest, err := h.client.Compute(hash, height, nonce)
+
+	return nil, digest, err
+}
+
+func (h *octopusHasher) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	return h.client.ComputeBatch(ctx, jobs)
+}
+
+func (h *octopusHasher) Epoch(height uint64) uint64 {
+	return h.client.Epoch(height)
+}
+
+func (h *octopusHasher) ChainEpoch(height uint64) uint64 {
+	return h.client.ChainEpoch(height)
+}
+
+func (h *octopusHasher) SeedHash(epoch uint64) []byte {
+	return h.client.SeedHash(epoch)
+}
+
+func (h *octopusHasher) CacheSize(epoch uint64) uint64 {
+	return h.client.CacheSize(epoch)
+}
+
+func (h *octopusHasher) DatasetSize(epoch uint64) uint64 {
+	return h.client.DatasetSize(epoch)
+}
+
+func (h *octopusHasher) CachedEpochs() []uint64 {
+	return h.client.CachedEpochs()
+}
+
+func (h *octopusHasher) StoredCaches() ([]StoredCache, error) {
+	return h.client.StoredCaches()
+}
+
+func (h *octopusHasher) VerifyStoredCache(ctx context.Context, epoch uint64) error {
+	return h.client.VerifyStoredCache(ctx, epoch)
+}
+
+func (h *octopusHasher) GenerateCaches(ctx context.Context, first, last uint64) error {
+	return h.client.GenerateCaches(ctx, first, last)
+}
+
+func (h *octopusHasher) SetEventHandler(handler func(CacheEvent)) {
+	h.client.SetEventHandler(handler)
+}
+
+func (h *octopusHasher) SetStorage(storage Storage) {
+	h.client.SetStorage(storage)
+}
+
+func (h *octopusHasher) SetCacheBudget(bytes uint64) {
+	h.client.SetCacheBudget(bytes)
+}
+
+func (h *octopusHasher) SetCacheLookAhead(epochs int) {
+	h.client.SetCacheLookAhead(epochs)
+}
+
+func (h *octopusHasher) PinEpoch(ctx context.Context, epoch uint64) error {
+	return h.client.PinEpoch(ctx, epoch)
+}
+
+func (h *octopusHasher) UnpinEpoch(epoch uint64) {
+	h.client.UnpinEpoch(epoch)
+}
+
+func (h *octopusHasher) Close() {
+	h.client.Close()
+}
+
+type autolykos2Hasher struct {
+	client *autolykos2.Client
+}
+
+func (h *autolykos2Hasher) Compute(msg []byte, height, nonce uint64) ([]byte, []byte, error) {
+	digest, err := h.client.Compute(msg, height, nonce)
+
+	return nil, digest, err
+}
+
+func (h *autolykos2Hasher) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	return h.client.ComputeBatch(ctx, jobs)
+}
+
+// heavyhashHasher uses the height argument as the block timestamp, since
+// kHeavyHash does not depend on the height.
+type heavyhashHasher struct {
+	client *heavyhash.Client
+}
+
+func (h *heavyhashHasher) Compute(hash []byte, timestamp, nonce uint64) ([]byte, []byte, error) {
+	digest, err := h.client.Compute(hash, int64(timestamp), nonce)
+
+	return nil, digest, err
+}
+
+func (h *heavyhashHasher) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	heavyhashJobs := make([]heavyhash.ComputeJob, len(jobs))
+	for i, job := range jobs {
+		heavyhashJobs[i] = heavyhash.ComputeJob{Hash: job.Hash, Timestamp: int64(job.Height), Nonce: job.Nonce}
+	}
+
+	return h.client.ComputeBatch(ctx, heavyhashJobs)
+}
+
+// eaglesongHasher builds the Nervos input (the 32 byte pow hash followed
+// by the nonce as a little endian uint128) and ignores the height.
+type eaglesongHasher struct {
+	client *eaglesong.Client
+}
+
+func eaglesongInput(hash []byte, nonce uint64) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, &powerr.LengthError{Field: "hash", Want: 32, Have: len(hash)}
+	}
+
+	input := make([]byte, 48)
+	copy(input, hash)
+	binary.LittleEndian.PutUint64(input[32:], nonce)
+
+	return input, nil
+}
+
+func (h *eaglesongHasher) Compute(hash []byte, height, nonce uint64) ([]byte, []byte, error) {
+	input, err := eaglesongInput(hash, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, h.client.Compute(input), nil
+}
+
+func (h *eaglesongHasher) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	results := make([]ComputeResult, len(jobs))
+	var inputs [][]byte
+	var indices []int
+	for i, job := range jobs {
+		input, err := eaglesongInput(job.Hash, job.Nonce)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		inputs = append(inputs, input)
+		indices = append(indices, i)
+	}
+
+	digests, err := h.client.ComputeBatch(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, digest := range digests {
+		results[indices[i]].Digest = digest
+	}
+
+	return results, nil
+}
+
+// cuckooVerifier decodes the solution as a list of little endian uint32 edges.
+type cuckooVerifier struct {
+	client *cuckoo.Client
+}
+
+func cuckooSols(soln []byte) ([]uint64, error) {
+	if len(soln)%4 != 0 {
+		return nil, &powerr.InputError{Field: "soln", Reason: "must be a multiple of 4 bytes"}
+	}
+
+	sols := make([]uint64, len(soln)/4)
+	for i := range sols {
+		sols[i] = uint64(binary.LittleEndian.Uint32(soln[i*4:]))
+	}
+
+	return sols, nil
+}
+
+func (v *cuckooVerifier) Verify(header, soln []byte) (bool, error) {
+	sols, err := cuckooSols(soln)
+	if err != nil {
+		return false, err
+	}
+
+	return v.client.Verify(header, sols)
+}
+
+func (v *cuckooVerifier) VerifyBatch(ctx context.Context, jobs []VerifyJob) ([]VerifyResult, error) {
+	results := make([]VerifyResult, len(jobs))
+	var cuckooJobs []cuckoo.VerifyJob
+	var indices []int
+	for i, job := range jobs {
+		sols, err := cuckooSols(job.Soln)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		cuckooJobs = append(cuckooJobs, cuckoo.VerifyJob{Header: job.Header, Sols: sols})
+		indices = append(indices, i)
+	}
+
+	cuckooResults, err := v.client.VerifyBatch(ctx, cuckooJobs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range cuckooResults {
+		results[indices[i]] = result
+	}
+
+	return results, nil
+}
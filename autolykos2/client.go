@@ -1,13 +1,30 @@
 package autolykos2
 
 import (
-	"fmt"
+	"context"
+	"math/big"
+
+	"github.com/sencha-dev/powkit/internal/batch"
+	"github.com/sencha-dev/powkit/internal/search"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
+// SearchResult is the nonce found by Search, along with its digest (the mix
+// is always nil).
+type SearchResult = search.Result
+
+// ComputeJob is a single nonce to compute with ComputeBatch, Hash holding
+// the message.
+type ComputeJob = batch.ComputeJob
+
+// ComputeResult is the outcome of a ComputeJob.
+type ComputeResult = batch.ComputeResult
+
 type Client struct {
-	k     uint32
-	n     uint32
-	nBase uint32
+	k       uint32
+	n       uint32
+	nBase   uint32
+	workers int
 }
 
 func New(k, n uint32) *Client {
@@ -26,8 +43,54 @@ func NewErgo() *Client {
 
 func (c *Client) Compute(msg []byte, height, nonce uint64) ([]byte, error) {
 	if len(msg) != 32 {
-		return nil, fmt.Errorf("msg must be 32 bytes")
+		return nil, &powerr.LengthError{Field: "msg", Want: 32, Have: len(msg)}
 	}
 
 	return compute(c.k, c.nBase, msg, nonce, height), nil
 }
+
+// SetWorkers sets the number of goroutines Search and ComputeBatch run on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
+// Search computes count nonces starting at startNonce and returns the lowest
+// one whose digest is less than or equal to target, or nil if none of them is.
+func (c *Client) Search(ctx context.Context, msg []byte, height, startNonce, count uint64, target *big.Int) (*SearchResult, error) {
+	if len(msg) != 32 {
+		return nil, &powerr.LengthError{Field: "msg", Want: 32, Have: len(msg)}
+	}
+
+	newCompute := func() search.ComputeFunc {
+		return func(nonce uint64) ([]byte, []byte, error) {
+			return nil, compute(c.k, c.nBase, msg, nonce, height), nil
+		}
+	}
+
+	return search.Search(ctx, startNonce, count, c.workers, target, newCompute)
+}
+
+// ComputeBatch computes every job across the workers. The results are in the
+// order of the jobs, the error is only returned if ctx is done before all of
+// them are computed.
+func (c *Client) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	results := make([]ComputeResult, len(jobs))
+	newWorker := func() func(int) {
+		return func(i int) {
+			job := jobs[i]
+			if len(job.Hash) != 32 {
+				results[i].Err = &powerr.LengthError{Field: "msg", Want: 32, Have: len(job.Hash)}
+				return
+			}
+
+			results[i] = ComputeResult{Digest: compute(c.k, c.nBase, job.Hash, job.Nonce, job.Height)}
+		}
+	}
+
+	if err := batch.Run(ctx, len(jobs), c.workers, newWorker); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -4,16 +4,19 @@ package beamhashiii
 
 import (
 	"encoding/binary"
-	"fmt"
 	"math/big"
 
 	"github.com/sencha-dev/powkit/internal/crypto"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
 const (
 	workBitsSize      = 448
 	collisionBitsSize = 24
 	numRounds         = 5
+	numIndices        = 1 << numRounds
+	indicesSize       = numIndices * (collisionBitsSize + 1) / 8
+	solutionSize      = indicesSize + 4
 )
 
 var (
@@ -37,10 +40,10 @@ func reverseBytes(b []byte) []byte {
 }
 
 func indicesFromMinimal(soln []byte) []uint32 {
-	streamBig := new(big.Int).SetBytes(reverseBytes(soln[:100]))
+	streamBig := new(big.Int).SetBytes(reverseBytes(soln[:indicesSize]))
 
-	indices := make([]uint32, 32)
-	for i := 0; i < 32; i++ {
+	indices := make([]uint32, numIndices)
+	for i := 0; i < numIndices; i++ {
 		indices[i] = uint32(new(big.Int).And(streamBig, indexMask).Uint64())
 		streamBig.Rsh(streamBig, collisionBitsSize+1)
 	}
@@ -48,6 +51,23 @@ func indicesFromMinimal(soln []byte) []uint32 {
 	return indices
 }
 
+// indicesToMinimal packs the indices as a little-endian stream of 25 bit
+// values followed by the extra nonce, the inverse of indicesFromMinimal.
+func indicesToMinimal(indices []uint32, extraNonce uint32) []byte {
+	streamBig := new(big.Int)
+	for i := len(indices) - 1; i >= 0; i-- {
+		streamBig.Lsh(streamBig, collisionBitsSize+1)
+		streamBig.Or(streamBig, new(big.Int).SetUint64(uint64(indices[i])))
+	}
+
+	soln := make([]byte, solutionSize)
+	streamBig.FillBytes(soln[:indicesSize])
+	copy(soln, reverseBytes(soln[:indicesSize]))
+	binary.LittleEndian.PutUint32(soln[indicesSize:], extraNonce)
+
+	return soln
+}
+
 type node struct {
 	bitset  *big.Int
 	indices []uint32
@@ -107,19 +127,19 @@ func validateSubtrees(n, k uint32, a, b *node) error {
 
 	// check hasCollision
 	if maskedA.Cmp(maskedB) != 0 {
-		return fmt.Errorf("collision")
+		return &powerr.SolutionError{Reason: powerr.ReasonCollision}
 	}
 
 	// check indicesBefore
 	if b.indices[0] < a.indices[0] {
-		return fmt.Errorf("out of order")
+		return &powerr.SolutionError{Reason: powerr.ReasonOutOfOrder}
 	}
 
 	// check distinctIndices
 	for _, i := range a.indices {
 		for _, j := range b.indices {
 			if i == j {
-				return fmt.Errorf("duplicate indices")
+				return &powerr.SolutionError{Reason: powerr.ReasonDuplicateIndices}
 			}
 		}
 	}
@@ -163,7 +183,7 @@ func initializePrePow(personal, header, soln []byte) []uint64 {
 
 	state := make([]byte, len(header)+4)
 	copy(state, header)
-	binary.LittleEndian.PutUint32(state[len(header):], binary.LittleEndian.Uint32(soln[100:]))
+	binary.LittleEndian.PutUint32(state[len(header):], binary.LittleEndian.Uint32(soln[indicesSize:]))
 
 	hash := crypto.Blake2b(state, personalState, 32)
 	prePow := []uint64{
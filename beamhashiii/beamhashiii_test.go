@@ -1,6 +1,8 @@
 package beamhashiii
 
 import (
+	"bytes"
+	"context"
 	"testing"
 )
 
@@ -90,5 +92,58 @@ func TestVerifyBeam(t *testing.T) {
 		} else if !valid {
 			t.Errorf("failed on %d: invalid solution", i)
 		}
+
+		indices, extraNonce, err := IndicesFromMinimal(tt.soln)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		}
+
+		soln, err := IndicesToMinimal(indices, extraNonce)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if !bytes.Equal(soln, tt.soln) {
+			t.Errorf("failed on %d: encoding mismatch: have %x, want %x", i, soln, tt.soln)
+		}
+
+		valid, err = NewBeam().VerifyIndices(tt.header, indices, extraNonce)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if !valid {
+			t.Errorf("failed on %d: invalid indices", i)
+		}
+
+		indices[0] = 1 << 25
+		if _, err := IndicesToMinimal(indices, extraNonce); err == nil {
+			t.Errorf("failed on %d: expected error for out of range index", i)
+		}
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	header := make([]byte, 40)
+	soln := make([]byte, SolutionSize)
+
+	jobs := []VerifyJob{
+		{Header: header, Soln: soln},
+		{Header: header[:39], Soln: soln},
+		{Header: header, Soln: soln[:SolutionSize-1]},
+	}
+
+	client := NewBeam()
+	client.SetWorkers(2)
+
+	results, err := client.VerifyBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, job := range jobs {
+		valid, err := client.Verify(job.Header, job.Soln)
+		if results[i].Valid != valid {
+			t.Errorf("failed on %d: valid mismatch: have %t, want %t", i, results[i].Valid, valid)
+		} else if (err != nil) != (results[i].Err != nil) {
+			t.Errorf("failed on %d: error mismatch: have %v, want %v", i, results[i].Err, err)
+		}
 	}
 }
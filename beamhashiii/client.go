@@ -1,13 +1,23 @@
 package beamhashiii
 
 import (
-	"fmt"
+	"context"
+
+	"github.com/sencha-dev/powkit/internal/batch"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
+// VerifyJob is a single solution to verify with VerifyBatch.
+type VerifyJob = batch.VerifyJob
+
+// VerifyResult is the outcome of a VerifyJob.
+type VerifyResult = batch.VerifyResult
+
 type Client struct {
 	n        uint32
 	k        uint32
 	personal []byte
+	workers  int
 }
 
 func New(n, k uint32, personal string) *Client {
@@ -24,12 +34,48 @@ func NewBeam() *Client {
 	return New(150, 5, "Beam-PoW")
 }
 
+// SetWorkers sets the number of goroutines VerifyBatch runs on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
 func (c *Client) Verify(header, soln []byte) (bool, error) {
 	if len(header) != 40 {
-		return false, fmt.Errorf("header must be 40 bytes")
-	} else if len(soln) != 104 {
-		return false, fmt.Errorf("soln must be 104 bytes")
+		return false, &powerr.LengthError{Field: "header", Want: 40, Have: len(header)}
+	} else if len(soln) != solutionSize {
+		return false, &powerr.LengthError{Field: "soln", Want: solutionSize, Have: len(soln)}
 	}
 
 	return verify(c.n, c.k, c.personal, header, soln)
 }
+
+// VerifyIndices verifies a solution given as its expanded list of indices
+// and the extra nonce stored in the last 4 bytes of the encoded solution.
+func (c *Client) VerifyIndices(header []byte, indices []uint32, extraNonce uint32) (bool, error) {
+	soln, err := IndicesToMinimal(indices, extraNonce)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Verify(header, soln)
+}
+
+// VerifyBatch verifies every job across the workers. The results are in the
+// order of the jobs, the error is only returned if ctx is done before all of
+// them are verified.
+func (c *Client) VerifyBatch(ctx context.Context, jobs []VerifyJob) ([]VerifyResult, error) {
+	results := make([]VerifyResult, len(jobs))
+	newWorker := func() func(int) {
+		return func(i int) {
+			valid, err := c.Verify(jobs[i].Header, jobs[i].Soln)
+			results[i] = VerifyResult{Valid: valid, Err: err}
+		}
+	}
+
+	if err := batch.Run(ctx, len(jobs), c.workers, newWorker); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
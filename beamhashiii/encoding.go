@@ -0,0 +1,37 @@
+package beamhashiii
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// SolutionSize is the length of an encoded solution: the 32 indices packed
+// as 25 bit little-endian values (100 bytes) followed by a 4 byte extra nonce.
+const SolutionSize = solutionSize
+
+// IndicesFromMinimal expands an encoded solution into its 32 indices and
+// its extra nonce.
+func IndicesFromMinimal(soln []byte) ([]uint32, uint32, error) {
+	if len(soln) != solutionSize {
+		return nil, 0, &powerr.LengthError{Field: "soln", Want: solutionSize, Have: len(soln)}
+	}
+
+	return indicesFromMinimal(soln), binary.LittleEndian.Uint32(soln[indicesSize:]), nil
+}
+
+// IndicesToMinimal encodes the 32 indices and the extra nonce of a solution.
+func IndicesToMinimal(indices []uint32, extraNonce uint32) ([]byte, error) {
+	if len(indices) != numIndices {
+		return nil, &powerr.LengthError{Field: "indices", Want: numIndices, Have: len(indices), Unit: "indices"}
+	}
+
+	for _, index := range indices {
+		if index>>(collisionBitsSize+1) != 0 {
+			return nil, &powerr.InputError{Field: "indices", Reason: fmt.Sprintf("index %d does not fit in %d bits", index, collisionBitsSize+1)}
+		}
+	}
+
+	return indicesToMinimal(indices, extraNonce), nil
+}
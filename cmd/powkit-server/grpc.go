@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/cmd/powkit-server/powkitpb"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// grpcError converts an error returned by the service to a gRPC status.
+func grpcError(err error) error {
+	code := codes.InvalidArgument
+	switch {
+	case errors.Is(err, errTooManyJobs), errors.Is(err, errBusy):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, powerr.ErrUnsupportedVariant):
+		code = codes.NotFound
+	}
+
+	return status.Error(code, err.Error())
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+type grpcServer struct {
+	powkitpb.UnimplementedPowkitServer
+	svc *service
+}
+
+func newGRPCServer(svc *service) *grpcServer {
+	return &grpcServer{svc: svc}
+}
+
+func computeJob(job *powkitpb.ComputeJob) powkit.ComputeJob {
+	return powkit.ComputeJob{Hash: job.GetHash(), Height: job.GetHeight(), Nonce: job.GetNonce()}
+}
+
+func verifyJob(job *powkitpb.VerifyJob) powkit.VerifyJob {
+	return powkit.VerifyJob{Header: job.GetHeader(), Soln: job.GetSolution()}
+}
+
+func (s *grpcServer) Compute(ctx context.Context, req *powkitpb.ComputeRequest) (*powkitpb.ComputeResponse, error) {
+	mix, digest, err := s.svc.compute(ctx, req.GetAlgo(), computeJob(req.GetJob()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &powkitpb.ComputeResponse{Mix: mix, Digest: digest}, nil
+}
+
+func (s *grpcServer) Verify(ctx context.Context, req *powkitpb.VerifyRequest) (*powkitpb.VerifyResponse, error) {
+	result, err := s.svc.verify(ctx, req.GetAlgo(), verifyJob(req.GetJob()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &powkitpb.VerifyResponse{Valid: result.Valid, Error: errorString(result.Err)}, nil
+}
+
+func (s *grpcServer) ComputeBatch(ctx context.Context, req *powkitpb.ComputeBatchRequest) (*powkitpb.ComputeBatchResponse, error) {
+	jobs := make([]powkit.ComputeJob, len(req.GetJobs()))
+	for i, job := range req.GetJobs() {
+		jobs[i] = computeJob(job)
+	}
+
+	results, err := s.svc.computeBatch(ctx, req.GetAlgo(), jobs)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &powkitpb.ComputeBatchResponse{Results: make([]*powkitpb.ComputeResult, len(results))}
+	for i, result := range results {
+		resp.Results[i] = &powkitpb.ComputeResult{
+			Mix:    result.Mix,
+			Digest: result.Digest,
+			Error:  errorString(result.Err),
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) VerifyBatch(ctx context.Context, req *powkitpb.VerifyBatchRequest) (*powkitpb.VerifyBatchResponse, error) {
+	jobs := make([]powkit.VerifyJob, len(req.GetJobs()))
+	for i, job := range req.GetJobs() {
+		jobs[i] = verifyJob(job)
+	}
+
+	results, err := s.svc.verifyBatch(ctx, req.GetAlgo(), jobs)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &powkitpb.VerifyBatchResponse{Results: make([]*powkitpb.VerifyResult, len(results))}
+	for i, result := range results {
+		resp.Results[i] = &powkitpb.VerifyResult{Valid: result.Valid, Error: errorString(result.Err)}
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) Epoch(ctx context.Context, req *powkitpb.EpochRequest) (*powkitpb.EpochResponse, error) {
+	info, err := s.svc.epoch(req.GetAlgo(), req.GetHeight())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &powkitpb.EpochResponse{
+		Epoch:       info.Epoch,
+		EpochIndex:  info.EpochIndex,
+		SeedHash:    info.SeedHash,
+		CacheSize:   info.CacheSize,
+		DatasetSize: info.DatasetSize,
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) CacheStatus(ctx context.Context, req *powkitpb.CacheStatusRequest) (*powkitpb.CacheStatusResponse, error) {
+	statuses, err := s.svc.cacheStatus(req.GetAlgo())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &powkitpb.CacheStatusResponse{Algos: make([]*powkitpb.AlgoCacheStatus, len(statuses))}
+	for i, status := range statuses {
+		resp.Algos[i] = &powkitpb.AlgoCacheStatus{Algo: status.Algo, Epochs: status.Epochs}
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) Algos(ctx context.Context, req *powkitpb.AlgosRequest) (*powkitpb.AlgosResponse, error) {
+	resp := &powkitpb.AlgosResponse{
+		Hashers:   powkit.Hashers(),
+		Verifiers: powkit.Verifiers(),
+	}
+
+	return resp, nil
+}
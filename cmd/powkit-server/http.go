@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// errBodyTooLarge is returned for request bodies over the configured limit.
+var errBodyTooLarge = errors.New("request body too large")
+
+// hexBytes is encoded as a hex string, with an optional 0x prefix.
+type hexBytes []byte
+
+func (b hexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+
+	return nil
+}
+
+// jsonUint64 accepts a number as well as a decimal or 0x prefixed hex
+// string, since JavaScript numbers cannot hold every uint64.
+type jsonUint64 uint64
+
+func (u *jsonUint64) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := strconv.ParseUint(value, 0, 64)
+	if err != nil {
+		return err
+	}
+	*u = jsonUint64(parsed)
+
+	return nil
+}
+
+type computeJobJSON struct {
+	Hash   hexBytes   `json:"hash"`
+	Height jsonUint64 `json:"height"`
+	Nonce  jsonUint64 `json:"nonce"`
+}
+
+func (j computeJobJSON) job() powkit.ComputeJob {
+	return powkit.ComputeJob{Hash: j.Hash, Height: uint64(j.Height), Nonce: uint64(j.Nonce)}
+}
+
+type computeResultJSON struct {
+	Mix    hexBytes `json:"mix,omitempty"`
+	Digest hexBytes `json:"digest,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+type verifyJobJSON struct {
+	Header   hexBytes `json:"header"`
+	Solution hexBytes `json:"solution"`
+}
+
+func (j verifyJobJSON) job() powkit.VerifyJob {
+	return powkit.VerifyJob{Header: j.Header, Soln: j.Solution}
+}
+
+type verifyResultJSON struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+func newVerifyResultJSON(result powkit.VerifyResult) verifyResultJSON {
+	out := verifyResultJSON{Valid: result.Valid}
+	if result.Err != nil {
+		out.Error = result.Err.Error()
+	}
+
+	return out
+}
+
+type computeRequestJSON struct {
+	Algo string `json:"algo"`
+	computeJobJSON
+}
+
+type verifyRequestJSON struct {
+	Algo string `json:"algo"`
+	verifyJobJSON
+}
+
+type computeBatchRequestJSON struct {
+	Algo string           `json:"algo"`
+	Jobs []computeJobJSON `json:"jobs"`
+}
+
+type verifyBatchRequestJSON struct {
+	Algo string          `json:"algo"`
+	Jobs []verifyJobJSON `json:"jobs"`
+}
+
+type epochResponseJSON struct {
+	Epoch       uint64   `json:"epoch"`
+	EpochIndex  uint64   `json:"epochIndex"`
+	SeedHash    hexBytes `json:"seedHash"`
+	CacheSize   uint64   `json:"cacheSize"`
+	DatasetSize uint64   `json:"datasetSize"`
+}
+
+type cacheStatusJSON struct {
+	Algo   string   `json:"algo"`
+	Epochs []uint64 `json:"epochs"`
+}
+
+type algosResponseJSON struct {
+	Hashers   []string `json:"hashers"`
+	Verifiers []string `json:"verifiers"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// httpStatus returns the status code of an error returned by the service.
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, errBodyTooLarge), errors.Is(err, errTooManyJobs):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, errBusy):
+		return http.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, powerr.ErrUnsupportedVariant):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+type httpHandler struct {
+	svc     *service
+	maxBody int64
+}
+
+// newHTTPHandler returns the handler of the JSON API, which encodes every
+// byte string as hex.
+func newHTTPHandler(svc *service, maxBody int64) http.Handler {
+	h := &httpHandler{svc: svc, maxBody: maxBody}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/compute", h.post(h.compute))
+	mux.HandleFunc("/v1/verify", h.post(h.verify))
+	mux.HandleFunc("/v1/compute/batch", h.post(h.computeBatch))
+	mux.HandleFunc("/v1/verify/batch", h.post(h.verifyBatch))
+	mux.HandleFunc("/v1/epoch", h.get(h.epoch))
+	mux.HandleFunc("/v1/cache", h.get(h.cacheStatus))
+	mux.HandleFunc("/v1/algos", h.get(h.algos))
+
+	return mux
+}
+
+func (h *httpHandler) writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func (h *httpHandler) writeError(w http.ResponseWriter, err error) {
+	h.writeJSON(w, httpStatus(err), errorJSON{Error: err.Error()})
+}
+
+func (h *httpHandler) post(handle func(w http.ResponseWriter, r *http.Request, body []byte)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			h.writeJSON(w, http.StatusMethodNotAllowed, errorJSON{Error: "method not allowed"})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBody+1))
+		if err != nil {
+			h.writeError(w, err)
+			return
+		} else if int64(len(body)) > h.maxBody {
+			h.writeError(w, fmt.Errorf("%w: limit %d bytes", errBodyTooLarge, h.maxBody))
+			return
+		}
+
+		handle(w, r, body)
+	}
+}
+
+func (h *httpHandler) get(handle http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			h.writeJSON(w, http.StatusMethodNotAllowed, errorJSON{Error: "method not allowed"})
+			return
+		}
+
+		handle(w, r)
+	}
+}
+
+func (h *httpHandler) compute(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req computeRequestJSON
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	mix, digest, err := h.svc.compute(r.Context(), req.Algo, req.job())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, computeResultJSON{Mix: mix, Digest: digest})
+}
+
+func (h *httpHandler) verify(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req verifyRequestJSON
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	result, err := h.svc.verify(r.Context(), req.Algo, req.job())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newVerifyResultJSON(result))
+}
+
+func (h *httpHandler) computeBatch(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req computeBatchRequestJSON
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	jobs := make([]powkit.ComputeJob, len(req.Jobs))
+	for i, job := range req.Jobs {
+		jobs[i] = job.job()
+	}
+
+	results, err := h.svc.computeBatch(r.Context(), req.Algo, jobs)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	out := make([]computeResultJSON, len(results))
+	for i, result := range results {
+		out[i] = computeResultJSON{Mix: result.Mix, Digest: result.Digest}
+		if result.Err != nil {
+			out[i].Error = result.Err.Error()
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"results": out})
+}
+
+func (h *httpHandler) verifyBatch(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req verifyBatchRequestJSON
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	jobs := make([]powkit.VerifyJob, len(req.Jobs))
+	for i, job := range req.Jobs {
+		jobs[i] = job.job()
+	}
+
+	results, err := h.svc.verifyBatch(r.Context(), req.Algo, jobs)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	out := make([]verifyResultJSON, len(results))
+	for i, result := range results {
+		out[i] = newVerifyResultJSON(result)
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"results": out})
+}
+
+func (h *httpHandler) epoch(w http.ResponseWriter, r *http.Request) {
+	var height uint64
+	if value := r.URL.Query().Get("height"); value != "" {
+		var err error
+		if height, err = strconv.ParseUint(value, 0, 64); err != nil {
+			h.writeError(w, fmt.Errorf("height: %v", err))
+			return
+		}
+	}
+
+	info, err := h.svc.epoch(r.URL.Query().Get("algo"), height)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, epochResponseJSON{
+		Epoch:       info.Epoch,
+		EpochIndex:  info.EpochIndex,
+		SeedHash:    info.SeedHash,
+		CacheSize:   info.CacheSize,
+		DatasetSize: info.DatasetSize,
+	})
+}
+
+func (h *httpHandler) cacheStatus(w http.ResponseWriter, r *http.Request) {
+	statuses, err := h.svc.cacheStatus(r.URL.Query().Get("algo"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	out := make([]cacheStatusJSON, len(statuses))
+	for i, status := range statuses {
+		out[i] = cacheStatusJSON{Algo: status.Algo, Epochs: status.Epochs}
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"algos": out})
+}
+
+func (h *httpHandler) algos(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, algosResponseJSON{
+		Hashers:   powkit.Hashers(),
+		Verifiers: powkit.Verifiers(),
+	})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("%w: limit %d bytes", errBodyTooLarge, 64), http.StatusRequestEntityTooLarge},
+		{fmt.Errorf("%w: have %d, limit %d", errTooManyJobs, 3, 2), http.StatusRequestEntityTooLarge},
+		{errBusy, http.StatusTooManyRequests},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{&powerr.VariantError{Kind: "algorithm", Name: "unknown"}, http.StatusNotFound},
+		{&powerr.LengthError{Field: "hash", Want: 32, Have: 31}, http.StatusBadRequest},
+		{errors.New("invalid character"), http.StatusBadRequest},
+	}
+
+	for i, tt := range tests {
+		if status := httpStatus(tt.err); status != tt.status {
+			t.Errorf("failed on %d: status mismatch: have %d, want %d", i, status, tt.status)
+		}
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	const kaspaRequest = `{"algo": "KAS", "hash": "81553a695a0588998c413792e74ce8b8f8a096d64b3ee47387372434485c0b6f", ` +
+		`"height": "0x000001848ca87c49", "nonce": "0x2f8400000eba167c"}`
+
+	handler := newHTTPHandler(newService(2, 1, nil), 256)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		output string
+	}{
+		{
+			method: http.MethodPost,
+			path:   "/v1/compute",
+			body:   kaspaRequest,
+			status: http.StatusOK,
+			output: `"digest":"000000001726686e851f02c584d7cc8a8fbe5938ecdb3ffa2ba16c260ee1fc40"`,
+		},
+		{
+			method: http.MethodPost,
+			path:   "/v1/compute",
+			body:   `{"algo": "KAS", "hash": "` + strings.Repeat("00", 256) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+			output: errBodyTooLarge.Error(),
+		},
+		{
+			method: http.MethodPost,
+			path:   "/v1/compute/batch",
+			body:   `{"algo": "KAS", "jobs": [{}, {}, {}]}`,
+			status: http.StatusRequestEntityTooLarge,
+			output: errTooManyJobs.Error(),
+		},
+		{
+			method: http.MethodPost,
+			path:   "/v1/compute",
+			body:   `{"algo": "unknown"}`,
+			status: http.StatusNotFound,
+		},
+		{
+			method: http.MethodPost,
+			path:   "/v1/compute",
+			body:   `{"algo": "KAS", "hash": "00"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			method: http.MethodPost,
+			path:   "/v1/verify",
+			body:   `{"algo": "ZEC", "header": "00", "solution": "00"}`,
+			status: http.StatusOK,
+			output: `"valid":false`,
+		},
+		{
+			method: http.MethodGet,
+			path:   "/v1/compute",
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			method: http.MethodGet,
+			path:   "/v1/epoch?algo=ETC&height=11700000",
+			status: http.StatusOK,
+			output: `"epoch":195,"epochIndex":390`,
+		},
+		{
+			method: http.MethodGet,
+			path:   "/v1/epoch?algo=ETC&height=x",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("failed on %d: status mismatch: have %d, want %d: %s", i, rec.Code, tt.status, rec.Body)
+		} else if !strings.Contains(rec.Body.String(), tt.output) {
+			t.Errorf("failed on %d: output mismatch: have %s, want %s", i, rec.Body, tt.output)
+		}
+	}
+}
+
+func TestHTTPHandlerBusy(t *testing.T) {
+	svc := newService(1, 1, nil)
+	handler := newHTTPHandler(svc, 256)
+
+	release, err := svc.acquire(context.Background(), "CKB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/compute", strings.NewReader(`{"algo": "CKB", "hash": "00"}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status mismatch: have %d, want %d: %s", rec.Code, http.StatusTooManyRequests, rec.Body)
+	}
+}
@@ -0,0 +1,129 @@
+// Command powkit-server exposes the algorithm registry of powkit over an
+// HTTP JSON API and gRPC, so that pool software written in other languages
+// can reuse the validated implementations.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/cmd/powkit-server/powkitpb"
+	"github.com/sencha-dev/powkit/internal/common"
+)
+
+func main() {
+	httpAddr := flag.String("http", ":8080", "address of the HTTP JSON API, empty to disable it")
+	grpcAddr := flag.String("grpc", ":9090", "address of the gRPC API, empty to disable it")
+	maxBody := flag.Int64("max-body", 1<<20, "maximum size of a request in bytes")
+	maxBatch := flag.Int("max-batch", 1024, "maximum number of jobs in a batch request")
+	concurrency := flag.Int("concurrency", runtime.NumCPU(), "maximum number of concurrent requests per algorithm")
+	timeout := flag.Duration("timeout", 30*time.Second, "maximum duration of a request, including the wait for a free slot")
+	storageKind := flag.String("storage", "file", "storage of the DAG caches: file (~/.powcache), heap or hugepage")
+	prebuilt := flag.String("prebuilt", "", "read-only directory of pre-generated caches, used before -storage")
+	flag.Parse()
+
+	if *httpAddr == "" && *grpcAddr == "" {
+		log.Fatal("powkit-server: both -http and -grpc are disabled")
+	} else if *concurrency < 1 || *maxBatch < 1 || *maxBody < 1 {
+		log.Fatal("powkit-server: -concurrency, -max-batch and -max-body must be positive")
+	}
+
+	var storage powkit.Storage
+	switch *storageKind {
+	case "file":
+	case "heap":
+		storage = powkit.NewHeapStorage()
+	case "hugepage":
+		storage = powkit.NewHugePageStorage()
+	default:
+		log.Fatalf("powkit-server: unknown -storage %s", *storageKind)
+	}
+
+	if *prebuilt != "" {
+		if storage == nil {
+			storage = powkit.NewFileStorage(common.DefaultDir(".powcache"))
+		}
+		storage = powkit.NewReadOnlyStorage(*prebuilt, storage)
+	}
+
+	svc := newService(*maxBatch, *concurrency, storage)
+	errs := make(chan error, 2)
+
+	var httpServer *http.Server
+	if *httpAddr != "" {
+		httpServer = &http.Server{
+			Addr:              *httpAddr,
+			Handler:           http.TimeoutHandler(newHTTPHandler(svc, *maxBody), *timeout, `{"error":"request timed out"}`),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			log.Printf("powkit-server: serving HTTP on %s", *httpAddr)
+			if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+				errs <- err
+			}
+		}()
+	}
+
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatalf("powkit-server: %v", err)
+		}
+
+		grpcServer = grpc.NewServer(
+			grpc.MaxRecvMsgSize(int(*maxBody)),
+			grpc.UnaryInterceptor(timeoutInterceptor(*timeout)),
+		)
+		powkitpb.RegisterPowkitServer(grpcServer, newGRPCServer(svc))
+
+		go func() {
+			log.Printf("powkit-server: serving gRPC on %s", *grpcAddr)
+			errs <- grpcServer.Serve(listener)
+		}()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errs:
+		log.Fatalf("powkit-server: %v", err)
+	case <-signals:
+	}
+
+	if httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+
+		httpServer.Shutdown(ctx)
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
+	svc.close()
+}
+
+// timeoutInterceptor bounds the duration of every gRPC call, unless the
+// client asked for a shorter deadline.
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,1505 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: cmd/powkit-server/powkitpb/powkit.proto
+
+package powkitpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ComputeJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash   []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Nonce  uint64 `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *ComputeJob) Reset() {
+	*x = ComputeJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeJob) ProtoMessage() {}
+
+func (x *ComputeJob) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeJob.ProtoReflect.Descriptor instead.
+func (*ComputeJob) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ComputeJob) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *ComputeJob) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ComputeJob) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+type ComputeResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mix    []byte `protobuf:"bytes,1,opt,name=mix,proto3" json:"mix,omitempty"`
+	Digest []byte `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	Error  string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ComputeResult) Reset() {
+	*x = ComputeResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeResult) ProtoMessage() {}
+
+func (x *ComputeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeResult.ProtoReflect.Descriptor instead.
+func (*ComputeResult) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ComputeResult) GetMix() []byte {
+	if x != nil {
+		return x.Mix
+	}
+	return nil
+}
+
+func (x *ComputeResult) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *ComputeResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ComputeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algo string      `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"`
+	Job  *ComputeJob `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *ComputeRequest) Reset() {
+	*x = ComputeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeRequest) ProtoMessage() {}
+
+func (x *ComputeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeRequest.ProtoReflect.Descriptor instead.
+func (*ComputeRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ComputeRequest) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+func (x *ComputeRequest) GetJob() *ComputeJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type ComputeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mix    []byte `protobuf:"bytes,1,opt,name=mix,proto3" json:"mix,omitempty"`
+	Digest []byte `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *ComputeResponse) Reset() {
+	*x = ComputeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeResponse) ProtoMessage() {}
+
+func (x *ComputeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeResponse.ProtoReflect.Descriptor instead.
+func (*ComputeResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ComputeResponse) GetMix() []byte {
+	if x != nil {
+		return x.Mix
+	}
+	return nil
+}
+
+func (x *ComputeResponse) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+type VerifyJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Header   []byte `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Solution []byte `protobuf:"bytes,2,opt,name=solution,proto3" json:"solution,omitempty"`
+}
+
+func (x *VerifyJob) Reset() {
+	*x = VerifyJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyJob) ProtoMessage() {}
+
+func (x *VerifyJob) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyJob.ProtoReflect.Descriptor instead.
+func (*VerifyJob) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyJob) GetHeader() []byte {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *VerifyJob) GetSolution() []byte {
+	if x != nil {
+		return x.Solution
+	}
+	return nil
+}
+
+type VerifyResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid bool   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *VerifyResult) Reset() {
+	*x = VerifyResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResult) ProtoMessage() {}
+
+func (x *VerifyResult) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResult.ProtoReflect.Descriptor instead.
+func (*VerifyResult) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyResult) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type VerifyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algo string     `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"`
+	Job  *VerifyJob `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VerifyRequest) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetJob() *VerifyJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type VerifyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid bool   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *VerifyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ComputeBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algo string        `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"`
+	Jobs []*ComputeJob `protobuf:"bytes,2,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *ComputeBatchRequest) Reset() {
+	*x = ComputeBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeBatchRequest) ProtoMessage() {}
+
+func (x *ComputeBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeBatchRequest.ProtoReflect.Descriptor instead.
+func (*ComputeBatchRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ComputeBatchRequest) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+func (x *ComputeBatchRequest) GetJobs() []*ComputeJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type ComputeBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*ComputeResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *ComputeBatchResponse) Reset() {
+	*x = ComputeBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeBatchResponse) ProtoMessage() {}
+
+func (x *ComputeBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeBatchResponse.ProtoReflect.Descriptor instead.
+func (*ComputeBatchResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ComputeBatchResponse) GetResults() []*ComputeResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type VerifyBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algo string       `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"`
+	Jobs []*VerifyJob `protobuf:"bytes,2,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *VerifyBatchRequest) Reset() {
+	*x = VerifyBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBatchRequest) ProtoMessage() {}
+
+func (x *VerifyBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBatchRequest.ProtoReflect.Descriptor instead.
+func (*VerifyBatchRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VerifyBatchRequest) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+func (x *VerifyBatchRequest) GetJobs() []*VerifyJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type VerifyBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*VerifyResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *VerifyBatchResponse) Reset() {
+	*x = VerifyBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBatchResponse) ProtoMessage() {}
+
+func (x *VerifyBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBatchResponse.ProtoReflect.Descriptor instead.
+func (*VerifyBatchResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VerifyBatchResponse) GetResults() []*VerifyResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type EpochRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algo   string `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"`
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (x *EpochRequest) Reset() {
+	*x = EpochRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EpochRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EpochRequest) ProtoMessage() {}
+
+func (x *EpochRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EpochRequest.ProtoReflect.Descriptor instead.
+func (*EpochRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EpochRequest) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+func (x *EpochRequest) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type EpochResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Epoch       uint64 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	SeedHash    []byte `protobuf:"bytes,2,opt,name=seed_hash,json=seedHash,proto3" json:"seed_hash,omitempty"`
+	CacheSize   uint64 `protobuf:"varint,3,opt,name=cache_size,json=cacheSize,proto3" json:"cache_size,omitempty"`
+	DatasetSize uint64 `protobuf:"varint,4,opt,name=dataset_size,json=datasetSize,proto3" json:"dataset_size,omitempty"`
+	EpochIndex  uint64 `protobuf:"varint,5,opt,name=epoch_index,json=epochIndex,proto3" json:"epoch_index,omitempty"`
+}
+
+func (x *EpochResponse) Reset() {
+	*x = EpochResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EpochResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EpochResponse) ProtoMessage() {}
+
+func (x *EpochResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EpochResponse.ProtoReflect.Descriptor instead.
+func (*EpochResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EpochResponse) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *EpochResponse) GetSeedHash() []byte {
+	if x != nil {
+		return x.SeedHash
+	}
+	return nil
+}
+
+func (x *EpochResponse) GetCacheSize() uint64 {
+	if x != nil {
+		return x.CacheSize
+	}
+	return 0
+}
+
+func (x *EpochResponse) GetDatasetSize() uint64 {
+	if x != nil {
+		return x.DatasetSize
+	}
+	return 0
+}
+
+func (x *EpochResponse) GetEpochIndex() uint64 {
+	if x != nil {
+		return x.EpochIndex
+	}
+	return 0
+}
+
+type CacheStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algo string `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"`
+}
+
+func (x *CacheStatusRequest) Reset() {
+	*x = CacheStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CacheStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStatusRequest) ProtoMessage() {}
+
+func (x *CacheStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStatusRequest.ProtoReflect.Descriptor instead.
+func (*CacheStatusRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CacheStatusRequest) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+type AlgoCacheStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algo   string   `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"`
+	Epochs []uint64 `protobuf:"varint,2,rep,packed,name=epochs,proto3" json:"epochs,omitempty"`
+}
+
+func (x *AlgoCacheStatus) Reset() {
+	*x = AlgoCacheStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlgoCacheStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlgoCacheStatus) ProtoMessage() {}
+
+func (x *AlgoCacheStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlgoCacheStatus.ProtoReflect.Descriptor instead.
+func (*AlgoCacheStatus) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AlgoCacheStatus) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+func (x *AlgoCacheStatus) GetEpochs() []uint64 {
+	if x != nil {
+		return x.Epochs
+	}
+	return nil
+}
+
+type CacheStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algos []*AlgoCacheStatus `protobuf:"bytes,1,rep,name=algos,proto3" json:"algos,omitempty"`
+}
+
+func (x *CacheStatusResponse) Reset() {
+	*x = CacheStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CacheStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStatusResponse) ProtoMessage() {}
+
+func (x *CacheStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStatusResponse.ProtoReflect.Descriptor instead.
+func (*CacheStatusResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CacheStatusResponse) GetAlgos() []*AlgoCacheStatus {
+	if x != nil {
+		return x.Algos
+	}
+	return nil
+}
+
+type AlgosRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AlgosRequest) Reset() {
+	*x = AlgosRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlgosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlgosRequest) ProtoMessage() {}
+
+func (x *AlgosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlgosRequest.ProtoReflect.Descriptor instead.
+func (*AlgosRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{17}
+}
+
+type AlgosResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hashers   []string `protobuf:"bytes,1,rep,name=hashers,proto3" json:"hashers,omitempty"`
+	Verifiers []string `protobuf:"bytes,2,rep,name=verifiers,proto3" json:"verifiers,omitempty"`
+}
+
+func (x *AlgosResponse) Reset() {
+	*x = AlgosResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlgosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlgosResponse) ProtoMessage() {}
+
+func (x *AlgosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlgosResponse.ProtoReflect.Descriptor instead.
+func (*AlgosResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AlgosResponse) GetHashers() []string {
+	if x != nil {
+		return x.Hashers
+	}
+	return nil
+}
+
+func (x *AlgosResponse) GetVerifiers() []string {
+	if x != nil {
+		return x.Verifiers
+	}
+	return nil
+}
+
+var File_cmd_powkit_server_powkitpb_powkit_proto protoreflect.FileDescriptor
+
+var file_cmd_powkit_server_powkitpb_powkit_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x63, 0x6d, 0x64, 0x2f, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2d, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2f, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x70, 0x62, 0x2f, 0x70, 0x6f, 0x77,
+	0x6b, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x70, 0x6f, 0x77, 0x6b, 0x69,
+	0x74, 0x2e, 0x76, 0x31, 0x22, 0x4e, 0x0a, 0x0a, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x4a,
+	0x6f, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6e,
+	0x6f, 0x6e, 0x63, 0x65, 0x22, 0x4f, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x03, 0x6d, 0x69, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4d, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x12, 0x27, 0x0a, 0x03, 0x6a,
+	0x6f, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52,
+	0x03, 0x6a, 0x6f, 0x62, 0x22, 0x3b, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x78, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6d, 0x69, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x22, 0x3f, 0x0a, 0x09, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4a, 0x6f, 0x62, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x3a, 0x0a, 0x0c, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4b,
+	0x0a, 0x0d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61,
+	0x6c, 0x67, 0x6f, 0x12, 0x26, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x4a, 0x6f, 0x62, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x22, 0x3c, 0x0a, 0x0e, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x54, 0x0a, 0x13, 0x43, 0x6f, 0x6d,
+	0x70, 0x75, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x61, 0x6c, 0x67, 0x6f, 0x12, 0x29, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22,
+	0x4a, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x52, 0x0a, 0x12, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x61, 0x6c, 0x67, 0x6f, 0x12, 0x28, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4a, 0x6f, 0x62, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22,
+	0x48, 0x0a, 0x13, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x3a, 0x0a, 0x0c, 0x45, 0x70, 0x6f,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c, 0x67,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x12, 0x16, 0x0a,
+	0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0xa5, 0x01, 0x0a, 0x0d, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x65, 0x65, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x73, 0x65, 0x65, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x63, 0x61, 0x63, 0x68, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x65, 0x70, 0x6f, 0x63, 0x68, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x28, 0x0a,
+	0x12, 0x43, 0x61, 0x63, 0x68, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x22, 0x3d, 0x0a, 0x0f, 0x41, 0x6c, 0x67, 0x6f, 0x43,
+	0x61, 0x63, 0x68, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c,
+	0x67, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x04, 0x52, 0x06,
+	0x65, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x22, 0x47, 0x0a, 0x13, 0x43, 0x61, 0x63, 0x68, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a,
+	0x05, 0x61, 0x6c, 0x67, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70,
+	0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6c, 0x67, 0x6f, 0x43, 0x61, 0x63,
+	0x68, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x61, 0x6c, 0x67, 0x6f, 0x73, 0x22,
+	0x0e, 0x0a, 0x0c, 0x41, 0x6c, 0x67, 0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x47, 0x0a, 0x0d, 0x41, 0x6c, 0x67, 0x6f, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x65,
+	0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x76,
+	0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x32, 0xee, 0x03, 0x0a, 0x06, 0x50, 0x6f, 0x77,
+	0x6b, 0x69, 0x74, 0x12, 0x40, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x12, 0x19,
+	0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x6f, 0x77, 0x6b,
+	0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12,
+	0x18, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x6f, 0x77, 0x6b,
+	0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0c, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x1e, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x1d, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x17, 0x2e, 0x70,
+	0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4c, 0x0a, 0x0b, 0x43, 0x61, 0x63, 0x68, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d,
+	0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a,
+	0x05, 0x41, 0x6c, 0x67, 0x6f, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x6c, 0x67, 0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x18, 0x2e, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6c, 0x67, 0x6f,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x65, 0x6e, 0x63, 0x68, 0x61, 0x2d, 0x64,
+	0x65, 0x76, 0x2f, 0x70, 0x6f, 0x77, 0x6b, 0x69, 0x74, 0x2f, 0x63, 0x6d, 0x64, 0x2f, 0x70, 0x6f,
+	0x77, 0x6b, 0x69, 0x74, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x6f, 0x77, 0x6b,
+	0x69, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_cmd_powkit_server_powkitpb_powkit_proto_rawDescOnce sync.Once
+	file_cmd_powkit_server_powkitpb_powkit_proto_rawDescData = file_cmd_powkit_server_powkitpb_powkit_proto_rawDesc
+)
+
+func file_cmd_powkit_server_powkitpb_powkit_proto_rawDescGZIP() []byte {
+	file_cmd_powkit_server_powkitpb_powkit_proto_rawDescOnce.Do(func() {
+		file_cmd_powkit_server_powkitpb_powkit_proto_rawDescData = protoimpl.X.CompressGZIP(file_cmd_powkit_server_powkitpb_powkit_proto_rawDescData)
+	})
+	return file_cmd_powkit_server_powkitpb_powkit_proto_rawDescData
+}
+
+var file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_cmd_powkit_server_powkitpb_powkit_proto_goTypes = []interface{}{
+	(*ComputeJob)(nil),           // 0: powkit.v1.ComputeJob
+	(*ComputeResult)(nil),        // 1: powkit.v1.ComputeResult
+	(*ComputeRequest)(nil),       // 2: powkit.v1.ComputeRequest
+	(*ComputeResponse)(nil),      // 3: powkit.v1.ComputeResponse
+	(*VerifyJob)(nil),            // 4: powkit.v1.VerifyJob
+	(*VerifyResult)(nil),         // 5: powkit.v1.VerifyResult
+	(*VerifyRequest)(nil),        // 6: powkit.v1.VerifyRequest
+	(*VerifyResponse)(nil),       // 7: powkit.v1.VerifyResponse
+	(*ComputeBatchRequest)(nil),  // 8: powkit.v1.ComputeBatchRequest
+	(*ComputeBatchResponse)(nil), // 9: powkit.v1.ComputeBatchResponse
+	(*VerifyBatchRequest)(nil),   // 10: powkit.v1.VerifyBatchRequest
+	(*VerifyBatchResponse)(nil),  // 11: powkit.v1.VerifyBatchResponse
+	(*EpochRequest)(nil),         // 12: powkit.v1.EpochRequest
+	(*EpochResponse)(nil),        // 13: powkit.v1.EpochResponse
+	(*CacheStatusRequest)(nil),   // 14: powkit.v1.CacheStatusRequest
+	(*AlgoCacheStatus)(nil),      // 15: powkit.v1.AlgoCacheStatus
+	(*CacheStatusResponse)(nil),  // 16: powkit.v1.CacheStatusResponse
+	(*AlgosRequest)(nil),         // 17: powkit.v1.AlgosRequest
+	(*AlgosResponse)(nil),        // 18: powkit.v1.AlgosResponse
+}
+var file_cmd_powkit_server_powkitpb_powkit_proto_depIdxs = []int32{
+	0,  // 0: powkit.v1.ComputeRequest.job:type_name -> powkit.v1.ComputeJob
+	4,  // 1: powkit.v1.VerifyRequest.job:type_name -> powkit.v1.VerifyJob
+	0,  // 2: powkit.v1.ComputeBatchRequest.jobs:type_name -> powkit.v1.ComputeJob
+	1,  // 3: powkit.v1.ComputeBatchResponse.results:type_name -> powkit.v1.ComputeResult
+	4,  // 4: powkit.v1.VerifyBatchRequest.jobs:type_name -> powkit.v1.VerifyJob
+	5,  // 5: powkit.v1.VerifyBatchResponse.results:type_name -> powkit.v1.VerifyResult
+	15, // 6: powkit.v1.CacheStatusResponse.algos:type_name -> powkit.v1.AlgoCacheStatus
+	2,  // 7: powkit.v1.Powkit.Compute:input_type -> powkit.v1.ComputeRequest
+	6,  // 8: powkit.v1.Powkit.Verify:input_type -> powkit.v1.VerifyRequest
+	8,  // 9: powkit.v1.Powkit.ComputeBatch:input_type -> powkit.v1.ComputeBatchRequest
+	10, // 10: powkit.v1.Powkit.VerifyBatch:input_type -> powkit.v1.VerifyBatchRequest
+	12, // 11: powkit.v1.Powkit.Epoch:input_type -> powkit.v1.EpochRequest
+	14, // 12: powkit.v1.Powkit.CacheStatus:input_type -> powkit.v1.CacheStatusRequest
+	17, // 13: powkit.v1.Powkit.Algos:input_type -> powkit.v1.AlgosRequest
+	3,  // 14: powkit.v1.Powkit.Compute:output_type -> powkit.v1.ComputeResponse
+	7,  // 15: powkit.v1.Powkit.Verify:output_type -> powkit.v1.VerifyResponse
+	9,  // 16: powkit.v1.Powkit.ComputeBatch:output_type -> powkit.v1.ComputeBatchResponse
+	11, // 17: powkit.v1.Powkit.VerifyBatch:output_type -> powkit.v1.VerifyBatchResponse
+	13, // 18: powkit.v1.Powkit.Epoch:output_type -> powkit.v1.EpochResponse
+	16, // 19: powkit.v1.Powkit.CacheStatus:output_type -> powkit.v1.CacheStatusResponse
+	18, // 20: powkit.v1.Powkit.Algos:output_type -> powkit.v1.AlgosResponse
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_cmd_powkit_server_powkitpb_powkit_proto_init() }
+func file_cmd_powkit_server_powkitpb_powkit_proto_init() {
+	if File_cmd_powkit_server_powkitpb_powkit_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EpochRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EpochResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CacheStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlgoCacheStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CacheStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlgosRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlgosResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_cmd_powkit_server_powkitpb_powkit_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cmd_powkit_server_powkitpb_powkit_proto_goTypes,
+		DependencyIndexes: file_cmd_powkit_server_powkitpb_powkit_proto_depIdxs,
+		MessageInfos:      file_cmd_powkit_server_powkitpb_powkit_proto_msgTypes,
+	}.Build()
+	File_cmd_powkit_server_powkitpb_powkit_proto = out.File
+	file_cmd_powkit_server_powkitpb_powkit_proto_rawDesc = nil
+	file_cmd_powkit_server_powkitpb_powkit_proto_goTypes = nil
+	file_cmd_powkit_server_powkitpb_powkit_proto_depIdxs = nil
+}
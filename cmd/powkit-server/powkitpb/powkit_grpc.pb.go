@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: cmd/powkit-server/powkitpb/powkit.proto
+
+package powkitpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Powkit_Compute_FullMethodName      = "/powkit.v1.Powkit/Compute"
+	Powkit_Verify_FullMethodName       = "/powkit.v1.Powkit/Verify"
+	Powkit_ComputeBatch_FullMethodName = "/powkit.v1.Powkit/ComputeBatch"
+	Powkit_VerifyBatch_FullMethodName  = "/powkit.v1.Powkit/VerifyBatch"
+	Powkit_Epoch_FullMethodName        = "/powkit.v1.Powkit/Epoch"
+	Powkit_CacheStatus_FullMethodName  = "/powkit.v1.Powkit/CacheStatus"
+	Powkit_Algos_FullMethodName        = "/powkit.v1.Powkit/Algos"
+)
+
+// PowkitClient is the client API for Powkit service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PowkitClient interface {
+	Compute(ctx context.Context, in *ComputeRequest, opts ...grpc.CallOption) (*ComputeResponse, error)
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	ComputeBatch(ctx context.Context, in *ComputeBatchRequest, opts ...grpc.CallOption) (*ComputeBatchResponse, error)
+	VerifyBatch(ctx context.Context, in *VerifyBatchRequest, opts ...grpc.CallOption) (*VerifyBatchResponse, error)
+	Epoch(ctx context.Context, in *EpochRequest, opts ...grpc.CallOption) (*EpochResponse, error)
+	CacheStatus(ctx context.Context, in *CacheStatusRequest, opts ...grpc.CallOption) (*CacheStatusResponse, error)
+	Algos(ctx context.Context, in *AlgosRequest, opts ...grpc.CallOption) (*AlgosResponse, error)
+}
+
+type powkitClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPowkitClient(cc grpc.ClientConnInterface) PowkitClient {
+	return &powkitClient{cc}
+}
+
+func (c *powkitClient) Compute(ctx context.Context, in *ComputeRequest, opts ...grpc.CallOption) (*ComputeResponse, error) {
+	out := new(ComputeResponse)
+	err := c.cc.Invoke(ctx, Powkit_Compute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powkitClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, Powkit_Verify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powkitClient) ComputeBatch(ctx context.Context, in *ComputeBatchRequest, opts ...grpc.CallOption) (*ComputeBatchResponse, error) {
+	out := new(ComputeBatchResponse)
+	err := c.cc.Invoke(ctx, Powkit_ComputeBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powkitClient) VerifyBatch(ctx context.Context, in *VerifyBatchRequest, opts ...grpc.CallOption) (*VerifyBatchResponse, error) {
+	out := new(VerifyBatchResponse)
+	err := c.cc.Invoke(ctx, Powkit_VerifyBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powkitClient) Epoch(ctx context.Context, in *EpochRequest, opts ...grpc.CallOption) (*EpochResponse, error) {
+	out := new(EpochResponse)
+	err := c.cc.Invoke(ctx, Powkit_Epoch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powkitClient) CacheStatus(ctx context.Context, in *CacheStatusRequest, opts ...grpc.CallOption) (*CacheStatusResponse, error) {
+	out := new(CacheStatusResponse)
+	err := c.cc.Invoke(ctx, Powkit_CacheStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powkitClient) Algos(ctx context.Context, in *AlgosRequest, opts ...grpc.CallOption) (*AlgosResponse, error) {
+	out := new(AlgosResponse)
+	err := c.cc.Invoke(ctx, Powkit_Algos_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PowkitServer is the server API for Powkit service.
+// All implementations must embed UnimplementedPowkitServer
+// for forward compatibility
+type PowkitServer interface {
+	Compute(context.Context, *ComputeRequest) (*ComputeResponse, error)
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	ComputeBatch(context.Context, *ComputeBatchRequest) (*ComputeBatchResponse, error)
+	VerifyBatch(context.Context, *VerifyBatchRequest) (*VerifyBatchResponse, error)
+	Epoch(context.Context, *EpochRequest) (*EpochResponse, error)
+	CacheStatus(context.Context, *CacheStatusRequest) (*CacheStatusResponse, error)
+	Algos(context.Context, *AlgosRequest) (*AlgosResponse, error)
+	mustEmbedUnimplementedPowkitServer()
+}
+
+// UnimplementedPowkitServer must be embedded to have forward compatible implementations.
+type UnimplementedPowkitServer struct {
+}
+
+func (UnimplementedPowkitServer) Compute(context.Context, *ComputeRequest) (*ComputeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Compute not implemented")
+}
+func (UnimplementedPowkitServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedPowkitServer) ComputeBatch(context.Context, *ComputeBatchRequest) (*ComputeBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComputeBatch not implemented")
+}
+func (UnimplementedPowkitServer) VerifyBatch(context.Context, *VerifyBatchRequest) (*VerifyBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyBatch not implemented")
+}
+func (UnimplementedPowkitServer) Epoch(context.Context, *EpochRequest) (*EpochResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Epoch not implemented")
+}
+func (UnimplementedPowkitServer) CacheStatus(context.Context, *CacheStatusRequest) (*CacheStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CacheStatus not implemented")
+}
+func (UnimplementedPowkitServer) Algos(context.Context, *AlgosRequest) (*AlgosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Algos not implemented")
+}
+func (UnimplementedPowkitServer) mustEmbedUnimplementedPowkitServer() {}
+
+// UnsafePowkitServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PowkitServer will
+// result in compilation errors.
+type UnsafePowkitServer interface {
+	mustEmbedUnimplementedPowkitServer()
+}
+
+func RegisterPowkitServer(s grpc.ServiceRegistrar, srv PowkitServer) {
+	s.RegisterService(&Powkit_ServiceDesc, srv)
+}
+
+func _Powkit_Compute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowkitServer).Compute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Powkit_Compute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowkitServer).Compute(ctx, req.(*ComputeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Powkit_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowkitServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Powkit_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowkitServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Powkit_ComputeBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputeBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowkitServer).ComputeBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Powkit_ComputeBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowkitServer).ComputeBatch(ctx, req.(*ComputeBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Powkit_VerifyBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowkitServer).VerifyBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Powkit_VerifyBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowkitServer).VerifyBatch(ctx, req.(*VerifyBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Powkit_Epoch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowkitServer).Epoch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Powkit_Epoch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowkitServer).Epoch(ctx, req.(*EpochRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Powkit_CacheStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CacheStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowkitServer).CacheStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Powkit_CacheStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowkitServer).CacheStatus(ctx, req.(*CacheStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Powkit_Algos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlgosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowkitServer).Algos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Powkit_Algos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowkitServer).Algos(ctx, req.(*AlgosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Powkit_ServiceDesc is the grpc.ServiceDesc for Powkit service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Powkit_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "powkit.v1.Powkit",
+	HandlerType: (*PowkitServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Compute",
+			Handler:    _Powkit_Compute_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _Powkit_Verify_Handler,
+		},
+		{
+			MethodName: "ComputeBatch",
+			Handler:    _Powkit_ComputeBatch_Handler,
+		},
+		{
+			MethodName: "VerifyBatch",
+			Handler:    _Powkit_VerifyBatch_Handler,
+		},
+		{
+			MethodName: "Epoch",
+			Handler:    _Powkit_Epoch_Handler,
+		},
+		{
+			MethodName: "CacheStatus",
+			Handler:    _Powkit_CacheStatus_Handler,
+		},
+		{
+			MethodName: "Algos",
+			Handler:    _Powkit_Algos_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cmd/powkit-server/powkitpb/powkit.proto",
+}
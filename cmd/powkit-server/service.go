@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+var (
+	// errTooManyJobs is returned for batches over the configured limit.
+	errTooManyJobs = errors.New("too many jobs")
+
+	// errBusy is returned when no slot of the algorithm frees up before the
+	// request times out.
+	errBusy = errors.New("too many concurrent requests")
+)
+
+// epochInfo describes the epoch of a height for a DAG based algorithm. Epoch
+// is the chain's epoch number and EpochIndex the sequential index the cache
+// status and the clients use, which differ after an epoch length change.
+type epochInfo struct {
+	Epoch       uint64
+	EpochIndex  uint64
+	SeedHash    []byte
+	CacheSize   uint64
+	DatasetSize uint64
+}
+
+// cacheStatus lists the epochs whose cache an algorithm holds in memory.
+type cacheStatus struct {
+	Algo   string
+	Epochs []uint64
+}
+
+// service holds one client per algorithm, created on first use so that the
+// DAG caches are shared by every request, and the slots that cap the number
+// of concurrent requests per algorithm. Both the HTTP and gRPC servers are
+// thin wrappers around it.
+type service struct {
+	maxBatch    int
+	concurrency int
+	storage     powkit.Storage // Storage of the DAG caches, the default if nil
+
+	mu        sync.Mutex
+	hashers   map[string]powkit.Hasher
+	verifiers map[string]powkit.Verifier
+	slots     map[string]chan struct{}
+}
+
+func newService(maxBatch, concurrency int, storage powkit.Storage) *service {
+	s := &service{
+		maxBatch:    maxBatch,
+		concurrency: concurrency,
+		storage:     storage,
+		hashers:     make(map[string]powkit.Hasher),
+		verifiers:   make(map[string]powkit.Verifier),
+		slots:       make(map[string]chan struct{}),
+	}
+
+	return s
+}
+
+func (s *service) hasher(algo string) (string, powkit.Hasher, error) {
+	name := strings.ToUpper(algo)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hasher, ok := s.hashers[name]; ok {
+		return name, hasher, nil
+	}
+
+	hasher, err := powkit.NewHasher(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.configure(name, hasher)
+	s.hashers[name] = hasher
+
+	return name, hasher, nil
+}
+
+func (s *service) verifier(algo string) (string, powkit.Verifier, error) {
+	name := strings.ToUpper(algo)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if verifier, ok := s.verifiers[name]; ok {
+		return name, verifier, nil
+	}
+
+	verifier, err := powkit.NewVerifier(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.configure(name, verifier)
+	s.verifiers[name] = verifier
+
+	return name, verifier, nil
+}
+
+// configure sets the storage of a DAG based hasher or verifier and logs the
+// stored caches that fail validation and are regenerated.
+func (s *service) configure(name string, client interface{}) {
+	dagHasher, ok := client.(powkit.DAGHasher)
+	if !ok {
+		return
+	}
+
+	if s.storage != nil {
+		dagHasher.SetStorage(s.storage)
+	}
+	dagHasher.SetEventHandler(func(event powkit.CacheEvent) {
+		if event.Type == powkit.CacheCorrupted {
+			log.Printf("powkit-server: %s: regenerating epoch %d: %v", name, event.Epoch, event.Err)
+		}
+	})
+}
+
+// acquire waits for a free slot of the algorithm, returning the function
+// that releases it.
+func (s *service) acquire(ctx context.Context, name string) (func(), error) {
+	s.mu.Lock()
+	slots, ok := s.slots[name]
+	if !ok {
+		slots = make(chan struct{}, s.concurrency)
+		s.slots[name] = slots
+	}
+	s.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, errBusy
+	}
+}
+
+func (s *service) compute(ctx context.Context, algo string, job powkit.ComputeJob) ([]byte, []byte, error) {
+	name, hasher, err := s.hasher(algo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release, err := s.acquire(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	return hasher.Compute(job.Hash, job.Height, job.Nonce)
+}
+
+// verify reports solutions failing verification, including malformed ones,
+// as invalid with their error rather than failing the request.
+func (s *service) verify(ctx context.Context, algo string, job powkit.VerifyJob) (powkit.VerifyResult, error) {
+	name, verifier, err := s.verifier(algo)
+	if err != nil {
+		return powkit.VerifyResult{}, err
+	}
+
+	release, err := s.acquire(ctx, name)
+	if err != nil {
+		return powkit.VerifyResult{}, err
+	}
+	defer release()
+
+	valid, err := verifier.Verify(job.Header, job.Soln)
+
+	return powkit.VerifyResult{Valid: valid, Err: err}, nil
+}
+
+func (s *service) checkBatch(jobs int) error {
+	if jobs > s.maxBatch {
+		return fmt.Errorf("%w: have %d, limit %d", errTooManyJobs, jobs, s.maxBatch)
+	}
+
+	return nil
+}
+
+func (s *service) computeBatch(ctx context.Context, algo string, jobs []powkit.ComputeJob) ([]powkit.ComputeResult, error) {
+	if err := s.checkBatch(len(jobs)); err != nil {
+		return nil, err
+	}
+
+	name, hasher, err := s.hasher(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquire(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return hasher.(powkit.BatchHasher).ComputeBatch(ctx, jobs)
+}
+
+func (s *service) verifyBatch(ctx context.Context, algo string, jobs []powkit.VerifyJob) ([]powkit.VerifyResult, error) {
+	if err := s.checkBatch(len(jobs)); err != nil {
+		return nil, err
+	}
+
+	name, verifier, err := s.verifier(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquire(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return verifier.(powkit.BatchVerifier).VerifyBatch(ctx, jobs)
+}
+
+// close releases the caches of the DAG based clients.
+func (s *service) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, hasher := range s.hashers {
+		if dagHasher, ok := hasher.(powkit.DAGHasher); ok {
+			dagHasher.Close()
+		}
+	}
+
+	for _, verifier := range s.verifiers {
+		if dagHasher, ok := verifier.(powkit.DAGHasher); ok {
+			dagHasher.Close()
+		}
+	}
+}
+
+func (s *service) dagHasher(algo string) (powkit.DAGHasher, error) {
+	_, hasher, err := s.hasher(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	dagHasher, ok := hasher.(powkit.DAGHasher)
+	if !ok {
+		return nil, &powerr.VariantError{Kind: "dag algorithm", Name: algo}
+	}
+
+	return dagHasher, nil
+}
+
+func (s *service) epoch(algo string, height uint64) (*epochInfo, error) {
+	hasher, err := s.dagHasher(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	epoch := hasher.Epoch(height)
+	info := &epochInfo{
+		Epoch:       hasher.ChainEpoch(height),
+		EpochIndex:  epoch,
+		SeedHash:    hasher.SeedHash(epoch),
+		CacheSize:   hasher.CacheSize(epoch),
+		DatasetSize: hasher.DatasetSize(epoch),
+	}
+
+	return info, nil
+}
+
+// cacheStatus returns the cached epochs of the algorithm, or of every DAG
+// based algorithm used so far if algo is empty.
+func (s *service) cacheStatus(algo string) ([]cacheStatus, error) {
+	if algo != "" {
+		hasher, err := s.dagHasher(algo)
+		if err != nil {
+			return nil, err
+		}
+
+		return []cacheStatus{{Algo: strings.ToUpper(algo), Epochs: hasher.CachedEpochs()}}, nil
+	}
+
+	s.mu.Lock()
+	var statuses []cacheStatus
+	for name, hasher := range s.hashers {
+		if dagHasher, ok := hasher.(powkit.DAGHasher); ok {
+			statuses = append(statuses, cacheStatus{Algo: name, Epochs: dagHasher.CachedEpochs()})
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Algo < statuses[j].Algo
+	})
+
+	return statuses, nil
+}
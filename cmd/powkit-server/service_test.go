@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+func TestAcquire(t *testing.T) {
+	svc := newService(1, 2, nil)
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := svc.acquire(context.Background(), "KAS")
+		if err != nil {
+			t.Fatalf("failed on %d: %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+
+	// every slot of the algorithm is taken, but not those of the others
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := svc.acquire(ctx, "KAS"); !errors.Is(err, errBusy) {
+		t.Errorf("expected busy error, have %v", err)
+	}
+
+	release, err := svc.acquire(context.Background(), "CKB")
+	if err != nil {
+		t.Errorf("expected a slot of another algorithm, have %v", err)
+	} else {
+		release()
+	}
+
+	releases[0]()
+	release, err = svc.acquire(context.Background(), "KAS")
+	if err != nil {
+		t.Errorf("expected a released slot, have %v", err)
+	} else {
+		release()
+	}
+	releases[1]()
+}
+
+func TestServiceErrors(t *testing.T) {
+	svc := newService(2, 1, nil)
+	jobs := make([]powkit.ComputeJob, 3)
+	verifyJobs := make([]powkit.VerifyJob, 3)
+
+	tests := []struct {
+		run      func() error
+		sentinel error
+	}{
+		{
+			run: func() error {
+				_, err := svc.computeBatch(context.Background(), "KAS", jobs)
+				return err
+			},
+			sentinel: errTooManyJobs,
+		},
+		{
+			run: func() error {
+				_, err := svc.verifyBatch(context.Background(), "ZEC", verifyJobs)
+				return err
+			},
+			sentinel: errTooManyJobs,
+		},
+		{
+			run: func() error {
+				_, _, err := svc.compute(context.Background(), "unknown", powkit.ComputeJob{})
+				return err
+			},
+			sentinel: powerr.ErrUnsupportedVariant,
+		},
+		{
+			run: func() error {
+				_, err := svc.epoch("KAS", 0)
+				return err
+			},
+			sentinel: powerr.ErrUnsupportedVariant,
+		},
+	}
+
+	for i, tt := range tests {
+		if err := tt.run(); !errors.Is(err, tt.sentinel) {
+			t.Errorf("failed on %d: have %v, want %v", i, err, tt.sentinel)
+		}
+	}
+}
+
+func TestEpoch(t *testing.T) {
+	svc := newService(1, 1, nil)
+
+	info, err := svc.epoch("etc", 11700000)
+	if err != nil {
+		t.Fatal(err)
+	} else if info.Epoch != 195 {
+		t.Errorf("epoch mismatch: have %d, want %d", info.Epoch, 195)
+	} else if info.EpochIndex != 390 {
+		t.Errorf("epoch index mismatch: have %d, want %d", info.EpochIndex, 390)
+	}
+}
+
+// corruptStorage reports every stored entry as corrupted, so that it is
+// regenerated on the heap.
+type corruptStorage struct {
+	powkit.Storage
+}
+
+func (s corruptStorage) Load(entry powkit.StorageEntry) (powkit.StorageBuffer, error) {
+	return nil, fmt.Errorf("%w: bad checksum", powerr.ErrCorruptedCache)
+}
+
+func TestConfigure(t *testing.T) {
+	var output bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&output)
+
+	svc := newService(1, 1, corruptStorage{powkit.NewHeapStorage()})
+
+	_, hasher, err := svc.hasher("etc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hasher.(powkit.DAGHasher).Close()
+
+	if _, _, err := hasher.Compute(make([]byte, 32), 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "powkit-server: ETC: regenerating epoch 0"; !strings.Contains(output.String(), want) {
+		t.Errorf("log mismatch: have %q, want %q", output.String(), want)
+	}
+}
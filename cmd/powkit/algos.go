@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/equihash"
+	"github.com/sencha-dev/powkit/powerr"
+	"github.com/sencha-dev/powkit/progpow"
+)
+
+// aliases maps algorithm names to the coin registered in powkit.
+var aliases = map[string]string{
+	"ethash":     "ETH",
+	"etchash":    "ETC",
+	"ubqhash":    "UBQ",
+	"ethashb3":   "HYP",
+	"kawpow":     "RVN",
+	"evrprogpow": "EVR",
+	"meowpow":    "MEWC",
+	"firopow":    "FIRO",
+	"octopus":    "CFX",
+	"autolykos2": "ERG",
+	"heavyhash":  "KAS",
+	"eaglesong":  "CKB",
+	"zelhash":    "FLUX",
+	"beamhash":   "BEAM",
+	"cuckoo":     "AE",
+	"cuckaroo":   "CTXC",
+	"progpow":    "PROGPOW-0.9.4",
+}
+
+// progpowHashers are the vanilla ProgPoW revisions, which are not coins
+// and so are not registered in powkit.
+var progpowHashers = map[string]func() powkit.Hasher{
+	"PROGPOW-0.9.2": func() powkit.Hasher { return progpow.NewProgPoW092() },
+	"PROGPOW-0.9.3": func() powkit.Hasher { return progpow.NewProgPoW093() },
+	"PROGPOW-0.9.4": func() powkit.Hasher { return progpow.NewProgPoW094() },
+}
+
+// resolve returns the coin name of an algorithm or coin name.
+func resolve(algo string) string {
+	if coin, ok := aliases[strings.ToLower(algo)]; ok {
+		return coin
+	}
+
+	return strings.ToUpper(algo)
+}
+
+func newHasher(algo string) (powkit.Hasher, error) {
+	name := resolve(algo)
+	if constructor, ok := progpowHashers[name]; ok {
+		return constructor(), nil
+	}
+
+	return powkit.NewHasher(name)
+}
+
+func newDAGHasher(algo string) (powkit.DAGHasher, error) {
+	hasher, err := newHasher(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	dagHasher, ok := hasher.(powkit.DAGHasher)
+	if !ok {
+		return nil, &powerr.VariantError{Kind: "dag algorithm", Name: algo}
+	}
+
+	return dagHasher, nil
+}
+
+func newKernelGenerator(algo string) (powkit.KernelGenerator, error) {
+	hasher, err := newHasher(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	generator, ok := hasher.(powkit.KernelGenerator)
+	if !ok {
+		return nil, &powerr.VariantError{Kind: "progpow algorithm", Name: algo}
+	}
+
+	return generator, nil
+}
+
+// newVerifier also accepts equihash-<n>-<k>[-<personal>], which uses the
+// "ZcashPoW" personalization by default.
+func newVerifier(algo string) (powkit.Verifier, error) {
+	if parts := strings.Split(algo, "-"); len(parts) >= 3 && strings.ToLower(parts[0]) == "equihash" {
+		n, errN := strconv.ParseUint(parts[1], 10, 32)
+		k, errK := strconv.ParseUint(parts[2], 10, 32)
+		if errN != nil || errK != nil || len(parts) > 4 {
+			return nil, &powerr.VariantError{Kind: "verifier", Name: algo}
+		}
+
+		personal := "ZcashPoW"
+		if len(parts) == 4 {
+			personal = parts[3]
+		}
+
+		return equihash.New(uint32(n), uint32(k), personal, false), nil
+	}
+
+	return powkit.NewVerifier(resolve(algo))
+}
+
+// algorithms returns the help text listing every accepted name.
+func algorithms() string {
+	var names []string
+	for alias, coin := range aliases {
+		names = append(names, fmt.Sprintf("%s (%s)", alias, coin))
+	}
+	sort.Strings(names)
+
+	var dagNames []string
+	for _, name := range powkit.Hashers() {
+		if _, err := newDAGHasher(name); err == nil {
+			dagNames = append(dagNames, name)
+		}
+	}
+	for name := range progpowHashers {
+		dagNames = append(dagNames, name)
+	}
+	sort.Strings(dagNames)
+
+	return fmt.Sprintf(`Hashers:   %s, %s
+Verifiers: %s, equihash-<n>-<k>[-<personal>]
+DAG:       %s
+Aliases:   %s`,
+		strings.Join(powkit.Hashers(), ", "), "PROGPOW-0.9.2, PROGPOW-0.9.3, PROGPOW-0.9.4",
+		strings.Join(powkit.Verifiers(), ", "),
+		strings.Join(dagNames, ", "),
+		strings.Join(names, ", "))
+}
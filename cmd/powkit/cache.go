@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/internal/common"
+	"github.com/sencha-dev/powkit/internal/dag"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+const cacheUsage = `Usage: powkit cache <command> [flags]
+
+Commands:
+  list      list the stored caches per chain and epoch with their sizes
+  verify    compare stored caches with freshly generated ones
+  generate  generate the caches of a range of epochs ahead of use
+  prune     remove stored caches by age, total size or revision
+
+Caches are stored in %s.
+`
+
+func storageDir() string {
+	return common.DefaultDir(".powcache")
+}
+
+func runCache(args []string) error {
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, cacheUsage, storageDir())
+		os.Exit(2)
+	}
+
+	commands := map[string]func([]string) error{
+		"list":     runCacheList,
+		"verify":   runCacheVerify,
+		"generate": runCacheGenerate,
+		"prune":    runCachePrune,
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown cache command %s\n\n"+cacheUsage, args[0], storageDir())
+		os.Exit(2)
+	}
+
+	return command(args[1:])
+}
+
+// dagHashers returns every DAG hasher by coin name.
+func dagHashers() map[string]powkit.DAGHasher {
+	hashers := make(map[string]powkit.DAGHasher)
+	for _, name := range append(powkit.Hashers(), "PROGPOW-0.9.2", "PROGPOW-0.9.3", "PROGPOW-0.9.4") {
+		if hasher, err := newDAGHasher(name); err == nil {
+			hashers[name] = hasher
+		}
+	}
+
+	return hashers
+}
+
+// formatBytes formats a size with binary units.
+func formatBytes(size int64) string {
+	const unit = 1 << 10
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value, prefix := float64(size)/unit, 0
+	for value >= unit && prefix < 3 {
+		value /= unit
+		prefix++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[prefix])
+}
+
+// parseBytes accepts a number of bytes with an optional K, M, G or T binary
+// suffix.
+func parseBytes(name, value string) (int64, error) {
+	shift := 0
+	if i := strings.IndexAny(strings.ToUpper(value), "KMGT"); i > 0 {
+		shift = 10 * (1 + strings.IndexByte("KMGT", strings.ToUpper(value)[i]))
+		value = value[:i]
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("--%s: invalid size %s", name, value)
+	}
+
+	return parsed << shift, nil
+}
+
+type storedCacheJSON struct {
+	Algo     string    `json:"algo,omitempty"`
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	Revision int       `json:"revision"`
+	Epoch    *uint64   `json:"epoch,omitempty"`
+	Seed     string    `json:"seed"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+	Path     string    `json:"path"`
+}
+
+func newStoredCacheJSON(algo string, file powkit.StoredCache) storedCacheJSON {
+	out := storedCacheJSON{
+		Algo:     algo,
+		Kind:     string(file.Kind),
+		Name:     file.Name,
+		Revision: file.Revision,
+		Seed:     hex.EncodeToString(file.Seed),
+		Size:     file.Size,
+		ModTime:  file.ModTime,
+		Path:     file.Path,
+	}
+	if file.HasEpoch {
+		epoch := file.Epoch
+		out.Epoch = &epoch
+	}
+
+	return out
+}
+
+func printStoredCaches(files []storedCacheJSON, asJSON bool) error {
+	if asJSON {
+		data, err := json.Marshal(files)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+
+		return nil
+	}
+
+	var total int64
+	for _, file := range files {
+		epoch := "-"
+		if file.Epoch != nil {
+			epoch = strconv.FormatUint(*file.Epoch, 10)
+		}
+
+		fmt.Printf("%-14s %-6s R%-4d epoch %-6s %10s  %s  %s\n", file.Algo, file.Kind, file.Revision, epoch,
+			formatBytes(file.Size), file.ModTime.Format("2006-01-02 15:04"), file.Path)
+		total += file.Size
+	}
+	fmt.Printf("%d files, %s\n", len(files), formatBytes(total))
+
+	return nil
+}
+
+func runCacheList(args []string) error {
+	flags := flag.NewFlagSet("cache list", flag.ExitOnError)
+	algo := flags.String("algo", "", "DAG algorithm or coin name, every chain if empty")
+	asJSON := flags.Bool("json", false, "print the files as JSON")
+	flags.Parse(args)
+
+	hashers := dagHashers()
+	if *algo != "" {
+		hasher, err := newDAGHasher(*algo)
+		if err != nil {
+			return err
+		}
+		hashers = map[string]powkit.DAGHasher{resolve(*algo): hasher}
+	}
+
+	names := make([]string, 0, len(hashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var files []storedCacheJSON
+	for _, name := range names {
+		stored, err := hashers[name].StoredCaches()
+		if err != nil {
+			return err
+		}
+
+		for _, file := range stored {
+			files = append(files, newStoredCacheJSON(name, file))
+		}
+	}
+
+	return printStoredCaches(files, *asJSON)
+}
+
+func runCacheVerify(args []string) error {
+	flags := flag.NewFlagSet("cache verify", flag.ExitOnError)
+	algo := flags.String("algo", "", "DAG algorithm or coin name (see powkit algos)")
+	epoch := flags.String("epoch", "", "epoch to verify, every stored epoch if empty")
+	flags.Parse(args)
+
+	hasher, err := newDAGHasher(*algo)
+	if err != nil {
+		return err
+	}
+
+	var epochs []uint64
+	if *epoch != "" {
+		value, err := parseUint("epoch", *epoch)
+		if err != nil {
+			return err
+		}
+		epochs = append(epochs, value)
+	} else {
+		stored, err := hasher.StoredCaches()
+		if err != nil {
+			return err
+		}
+
+		for _, file := range stored {
+			if file.HasEpoch && file.Kind == dag.FileCache {
+				epochs = append(epochs, file.Epoch)
+			}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var corrupted int
+	for _, epoch := range epochs {
+		err := hasher.VerifyStoredCache(ctx, epoch)
+		switch {
+		case err == nil:
+			fmt.Printf("epoch %d: ok\n", epoch)
+		case errors.Is(err, powerr.ErrCorruptedCache), errors.Is(err, os.ErrNotExist):
+			fmt.Printf("epoch %d: %v\n", epoch, err)
+			corrupted++
+		default:
+			return err
+		}
+	}
+
+	if corrupted > 0 {
+		return fmt.Errorf("%d of %d caches failed verification", corrupted, len(epochs))
+	}
+
+	return nil
+}
+
+func runCacheGenerate(args []string) error {
+	flags := flag.NewFlagSet("cache generate", flag.ExitOnError)
+	algo := flags.String("algo", "", "DAG algorithm or coin name (see powkit algos)")
+	from := flags.String("from", "", "first epoch to generate")
+	to := flags.String("to", "", "last epoch to generate, --from if empty")
+	flags.Parse(args)
+
+	hasher, err := newDAGHasher(*algo)
+	if err != nil {
+		return err
+	}
+
+	first, err := parseUint("from", *from)
+	if err != nil {
+		return err
+	}
+
+	last := first
+	if *to != "" {
+		if last, err = parseUint("to", *to); err != nil {
+			return err
+		} else if last < first {
+			return fmt.Errorf("--to must not be lower than --from")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for epoch := first; epoch <= last && epoch >= first; epoch++ {
+		start := time.Now()
+		if err := hasher.GenerateCaches(ctx, epoch, epoch); err != nil {
+			return err
+		}
+
+		fmt.Printf("epoch %d: %s in %s\n", epoch, formatBytes(int64(hasher.CacheSize(epoch))), time.Since(start).Round(time.Millisecond))
+	}
+
+	return nil
+}
+
+func runCachePrune(args []string) error {
+	flags := flag.NewFlagSet("cache prune", flag.ExitOnError)
+	maxAge := flags.Duration("max-age", 0, "remove the caches not modified for longer, such as 720h")
+	maxBytes := flags.String("max-bytes", "", "remove the oldest caches until the rest fit, such as 512M")
+	staleRevisions := flags.Bool("stale-revisions", false, "remove the caches superseded by a higher revision")
+	dryRun := flags.Bool("dry-run", false, "only print the caches that would be removed")
+	asJSON := flags.Bool("json", false, "print the removed files as JSON")
+	flags.Parse(args)
+
+	opts := dag.PruneOptions{
+		MaxAge:         *maxAge,
+		StaleRevisions: *staleRevisions,
+		DryRun:         *dryRun,
+	}
+
+	if *maxBytes != "" {
+		var err error
+		if opts.MaxBytes, err = parseBytes("max-bytes", *maxBytes); err != nil {
+			return err
+		}
+	}
+
+	if opts.MaxAge <= 0 && opts.MaxBytes <= 0 && !opts.StaleRevisions {
+		return fmt.Errorf("one of --max-age, --max-bytes or --stale-revisions is required")
+	}
+
+	removed, err := dag.PruneStorage(storageDir(), opts)
+
+	files := make([]storedCacheJSON, len(removed))
+	for i, file := range removed {
+		files[i] = newStoredCacheJSON(file.Name, file)
+	}
+
+	if printErr := printStoredCaches(files, *asJSON); printErr != nil {
+		return printErr
+	}
+
+	return err
+}
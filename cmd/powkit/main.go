@@ -0,0 +1,320 @@
+// Command powkit computes and verifies proofs of work from the shell, so that
+// disputed shares can be reproduced without writing Go.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sencha-dev/powkit"
+	"github.com/sencha-dev/powkit/target"
+)
+
+const usage = `Usage: powkit <command> [flags]
+
+Commands:
+  compute   compute the mix and digest of a header hash, height and nonce
+  verify    verify a solution for a header
+  seedhash  print the seed hash of the epoch of a height
+  epoch     print the epoch and epoch index of a height with its seed hash and sizes
+  algos     list the supported algorithms
+  cache     manage the stored DAG caches
+  kernel    generate the CUDA or OpenCL ProgPoW kernel of the period of a height
+
+Run "powkit <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func([]string) error{
+		"compute":  runCompute,
+		"verify":   runVerify,
+		"seedhash": runSeedHash,
+		"epoch":    runEpoch,
+		"algos":    runAlgos,
+		"cache":    runCache,
+		"kernel":   runKernel,
+	}
+
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err := command(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "powkit %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// output is a list of named values, printed either one per line or as a
+// JSON object.
+type output struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *output) add(key string, value interface{}) {
+	if o.values == nil {
+		o.values = make(map[string]interface{})
+	}
+
+	o.keys = append(o.keys, key)
+	o.values[key] = value
+}
+
+func (o *output) print(asJSON bool) error {
+	if asJSON {
+		data, err := json.Marshal(o.values)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+
+		return nil
+	}
+
+	for _, key := range o.keys {
+		fmt.Printf("%s: %v\n", key, o.values[key])
+	}
+
+	return nil
+}
+
+func decodeHex(name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("--%s is required", name)
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("--%s: %v", name, err)
+	}
+
+	return data, nil
+}
+
+// parseUint accepts decimal values as well as 0x prefixed hex values.
+func parseUint(name, value string) (uint64, error) {
+	parsed, err := strconv.ParseUint(value, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("--%s: %v", name, err)
+	}
+
+	return parsed, nil
+}
+
+func runCompute(args []string) error {
+	flags := flag.NewFlagSet("compute", flag.ExitOnError)
+	algo := flags.String("algo", "", "algorithm or coin name (see powkit algos)")
+	header := flags.String("header", "", "hex encoded header hash (the message for autolykos2)")
+	height := flags.String("height", "0", "block height (the timestamp for heavyhash)")
+	nonce := flags.String("nonce", "0", "nonce, decimal or 0x prefixed hex")
+	targetHex := flags.String("target", "", "optional hex encoded target to check the digest against")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	flags.Parse(args)
+
+	hash, err := decodeHex("header", *header)
+	if err != nil {
+		return err
+	}
+
+	heightValue, err := parseUint("height", *height)
+	if err != nil {
+		return err
+	}
+
+	nonceValue, err := parseUint("nonce", *nonce)
+	if err != nil {
+		return err
+	}
+
+	hasher, err := newHasher(*algo)
+	if err != nil {
+		return err
+	}
+
+	mix, digest, err := hasher.Compute(hash, heightValue, nonceValue)
+	if err != nil {
+		return err
+	}
+
+	var out output
+	out.add("algo", resolve(*algo))
+	out.add("height", heightValue)
+	out.add("nonce", fmt.Sprintf("0x%016x", nonceValue))
+	if mix != nil {
+		out.add("mix", hex.EncodeToString(mix))
+	}
+	out.add("digest", hex.EncodeToString(digest))
+
+	if *targetHex != "" {
+		targetValue, ok := new(big.Int).SetString(strings.TrimPrefix(*targetHex, "0x"), 16)
+		if !ok {
+			return fmt.Errorf("--target: invalid hex %s", *targetHex)
+		}
+
+		out.add("meetsTarget", target.MeetsTarget(digest, targetValue))
+	}
+
+	return out.print(*asJSON)
+}
+
+func runVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	algo := flags.String("algo", "", "algorithm or coin name (see powkit algos)")
+	header := flags.String("header", "", "hex encoded header")
+	solution := flags.String("solution", "", "hex encoded solution (little endian uint32 edges for cuckoo)")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	flags.Parse(args)
+
+	headerBytes, err := decodeHex("header", *header)
+	if err != nil {
+		return err
+	}
+
+	soln, err := decodeHex("solution", *solution)
+	if err != nil {
+		return err
+	}
+
+	verifier, err := newVerifier(*algo)
+	if err != nil {
+		return err
+	}
+
+	valid, err := verifier.Verify(headerBytes, soln)
+
+	var out output
+	out.add("algo", *algo)
+	out.add("valid", valid)
+	if err != nil {
+		out.add("error", err.Error())
+	}
+
+	if err := out.print(*asJSON); err != nil {
+		return err
+	} else if !valid {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+func parseEpochFlags(name string, args []string) (powkit.DAGHasher, uint64, bool, error) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	algo := flags.String("algo", "", "DAG algorithm or coin name (see powkit algos)")
+	height := flags.String("height", "0", "block height")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	flags.Parse(args)
+
+	heightValue, err := parseUint("height", *height)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	client, err := newDAGHasher(*algo)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	return client, heightValue, *asJSON, nil
+}
+
+func runSeedHash(args []string) error {
+	client, height, asJSON, err := parseEpochFlags("seedhash", args)
+	if err != nil {
+		return err
+	}
+
+	seed := hex.EncodeToString(client.SeedHash(client.Epoch(height)))
+	if !asJSON {
+		fmt.Println(seed)
+
+		return nil
+	}
+
+	var out output
+	out.add("seedHash", seed)
+
+	return out.print(true)
+}
+
+func runEpoch(args []string) error {
+	client, height, asJSON, err := parseEpochFlags("epoch", args)
+	if err != nil {
+		return err
+	}
+
+	// the epoch index differs from the chain's epoch after an epoch length
+	// change, and is what the cache commands take
+	epoch := client.Epoch(height)
+
+	var out output
+	out.add("height", height)
+	out.add("epoch", client.ChainEpoch(height))
+	out.add("epochIndex", epoch)
+	out.add("seedHash", hex.EncodeToString(client.SeedHash(epoch)))
+	out.add("cacheSize", client.CacheSize(epoch))
+	out.add("datasetSize", client.DatasetSize(epoch))
+
+	return out.print(asJSON)
+}
+
+func runKernel(args []string) error {
+	flags := flag.NewFlagSet("kernel", flag.ExitOnError)
+	algo := flags.String("algo", "", "ProgPoW algorithm or coin name (kawpow, firopow, progpow-0.9.x)")
+	height := flags.String("height", "0", "block height")
+	lang := flags.String("lang", "cuda", "kernel language, cuda or opencl")
+	out := flags.String("out", "", "file to write the kernel to, stdout if empty")
+	flags.Parse(args)
+
+	heightValue, err := parseUint("height", *height)
+	if err != nil {
+		return err
+	}
+
+	languages := map[string]powkit.KernelLanguage{
+		"cuda":   powkit.KernelCUDA,
+		"opencl": powkit.KernelOpenCL,
+	}
+
+	language, ok := languages[strings.ToLower(*lang)]
+	if !ok {
+		return fmt.Errorf("--lang: unknown language %s", *lang)
+	}
+
+	generator, err := newKernelGenerator(*algo)
+	if err != nil {
+		return err
+	}
+
+	source, err := generator.Kernel(heightValue, language)
+	if err != nil {
+		return err
+	} else if *out == "" {
+		fmt.Print(source)
+
+		return nil
+	}
+
+	return os.WriteFile(*out, []byte(source), 0644)
+}
+
+func runAlgos(args []string) error {
+	fmt.Println(algorithms())
+
+	return nil
+}
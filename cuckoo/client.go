@@ -1,10 +1,13 @@
 package cuckoo
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 
+	"github.com/sencha-dev/powkit/internal/batch"
 	"github.com/sencha-dev/powkit/internal/crypto"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
 type CuckooVariant int
@@ -18,13 +21,24 @@ const (
 	Cuckarooz
 )
 
+// VerifyJob is a single solution to verify with VerifyBatch.
+type VerifyJob struct {
+	Header []byte
+	Sols   []uint64
+}
+
+// VerifyResult is the outcome of a VerifyJob.
+type VerifyResult = batch.VerifyResult
+
 type Client struct {
 	variant   CuckooVariant
 	proofSize int
 	edgeBits  int
 	edgeMask  uint64
+	nodeMask  uint64
 	sipnode   crypto.SipNodeFunc
 	sipblock  crypto.SipBlockFunc
+	workers   int
 }
 
 func newClient(variant CuckooVariant, edgeBits, proofSize int, sipnode crypto.SipNodeFunc, sipblock crypto.SipBlockFunc) *Client {
@@ -37,6 +51,17 @@ func newClient(variant CuckooVariant, edgeBits, proofSize int, sipnode crypto.Si
 		sipblock:  sipblock,
 	}
 
+	// the number of node bits differs from the number of edge bits
+	// for the directed and single partition variants
+	switch variant {
+	case Cuckarood:
+		c.nodeMask = c.edgeMask >> 1
+	case Cuckarooz:
+		c.nodeMask = c.edgeMask<<1 | 1
+	default:
+		c.nodeMask = c.edgeMask
+	}
+
 	return c
 }
 
@@ -48,6 +73,18 @@ func NewAeternity() *Client {
 	return NewCuckoo(29, 42, crypto.SipNode24Legacy, nil)
 }
 
+func NewCuckatoo(edgeBits, proofSize int, sipnode crypto.SipNodeFunc, sipblock crypto.SipBlockFunc) *Client {
+	return newClient(Cuckatoo, edgeBits, proofSize, sipnode, sipblock)
+}
+
+func NewGrinC31() *Client {
+	return NewCuckatoo(31, 42, crypto.SipNode24, nil)
+}
+
+func NewGrinC32() *Client {
+	return NewCuckatoo(32, 42, crypto.SipNode24, nil)
+}
+
 func NewCuckaroo(edgeBits, proofSize int, sipnode crypto.SipNodeFunc, sipblock crypto.SipBlockFunc) *Client {
 	return newClient(Cuckaroo, edgeBits, proofSize, sipnode, sipblock)
 }
@@ -56,12 +93,58 @@ func NewCortex() *Client {
 	return NewCuckaroo(30, 42, nil, crypto.SipBlock48)
 }
 
-func (c *Client) Verify(header []byte, sols []uint64) (bool, error) {
-	if len(sols) != c.proofSize {
-		return false, fmt.Errorf("sols must be %d uint64s", c.proofSize)
+func NewCuckarood(edgeBits, proofSize int, sipnode crypto.SipNodeFunc, sipblock crypto.SipBlockFunc) *Client {
+	return newClient(Cuckarood, edgeBits, proofSize, sipnode, sipblock)
+}
+
+func NewCuckaroom(edgeBits, proofSize int, sipnode crypto.SipNodeFunc, sipblock crypto.SipBlockFunc) *Client {
+	return newClient(Cuckaroom, edgeBits, proofSize, sipnode, sipblock)
+}
+
+func NewCuckarooz(edgeBits, proofSize int, sipnode crypto.SipNodeFunc, sipblock crypto.SipBlockFunc) *Client {
+	return newClient(Cuckarooz, edgeBits, proofSize, sipnode, sipblock)
+}
+
+// Grin hard fork heights switching the secondary proof of work.
+const (
+	grinCuckaroodHeight = 262080
+	grinCuckaroomHeight = 524160
+	grinCuckaroozHeight = 786240
+)
+
+func NewGrinCuckaroo29() *Client {
+	return NewCuckaroo(29, 42, nil, crypto.SipBlock24)
+}
+
+func NewGrinCuckarood29() *Client {
+	return NewCuckarood(29, 42, nil, crypto.SipBlock24Rot25)
+}
+
+func NewGrinCuckaroom29() *Client {
+	return NewCuckaroom(29, 42, nil, crypto.SipBlock24XorAll)
+}
+
+func NewGrinCuckarooz29() *Client {
+	return NewCuckarooz(29, 42, nil, crypto.SipBlock24XorAll)
+}
+
+// NewGrinSecondary returns the secondary proof of work used by Grin at the
+// given height, which changed with each of the first three hard forks.
+func NewGrinSecondary(height uint64) *Client {
+	switch {
+	case height < grinCuckaroodHeight:
+		return NewGrinCuckaroo29()
+	case height < grinCuckaroomHeight:
+		return NewGrinCuckarood29()
+	case height < grinCuckaroozHeight:
+		return NewGrinCuckaroom29()
+	default:
+		return NewGrinCuckarooz29()
 	}
+}
 
-	// create siphash keys
+// siphashKeys creates the siphash keys from the blake2b hash of the header.
+func siphashKeys(header []byte) [4]uint64 {
 	hash := crypto.Blake2b256(header)
 	keys := [4]uint64{
 		binary.LittleEndian.Uint64(hash[0:8]),
@@ -70,12 +153,57 @@ func (c *Client) Verify(header []byte, sols []uint64) (bool, error) {
 		binary.LittleEndian.Uint64(hash[24:32]),
 	}
 
+	return keys
+}
+
+// SetWorkers sets the number of goroutines VerifyBatch runs on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
+func (c *Client) Verify(header []byte, sols []uint64) (bool, error) {
+	if len(sols) != c.proofSize {
+		return false, &powerr.LengthError{Field: "sols", Want: c.proofSize, Have: len(sols), Unit: "uint64s"}
+	}
+
+	return c.verify(siphashKeys(header), sols)
+}
+
+func (c *Client) verify(keys [4]uint64, sols []uint64) (bool, error) {
 	switch c.variant {
 	case Cuckoo:
 		return c.cuckoo(keys, sols)
+	case Cuckatoo:
+		return c.cuckatoo(keys, sols)
 	case Cuckaroo:
 		return c.cuckaroo(keys, sols)
+	case Cuckarood:
+		return c.cuckarood(keys, sols)
+	case Cuckaroom:
+		return c.cuckaroom(keys, sols)
+	case Cuckarooz:
+		return c.cuckarooz(keys, sols)
 	default:
-		return false, fmt.Errorf("unsupported cuckoo variant")
+		return false, &powerr.VariantError{Kind: "cuckoo variant", Name: fmt.Sprint(int(c.variant))}
 	}
 }
+
+// VerifyBatch verifies every job across the workers. The results are in the
+// order of the jobs, the error is only returned if ctx is done before all of
+// them are verified.
+func (c *Client) VerifyBatch(ctx context.Context, jobs []VerifyJob) ([]VerifyResult, error) {
+	results := make([]VerifyResult, len(jobs))
+	newWorker := func() func(int) {
+		return func(i int) {
+			valid, err := c.Verify(jobs[i].Header, jobs[i].Sols)
+			results[i] = VerifyResult{Valid: valid, Err: err}
+		}
+	}
+
+	if err := batch.Run(ctx, len(jobs), c.workers, newWorker); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
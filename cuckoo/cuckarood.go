@@ -0,0 +1,68 @@
+// Copyright (c) 2013-2020 John Tromp
+
+package cuckoo
+
+// cuckarood verifies a cycle on the Cuckarood graph, a directed graph where
+// edges alternate between odd and even edge nonces and each node has half
+// the bits of an edge (Grin's secondary proof of work from 262080 to 524159).
+func (c *Client) cuckarood(siphashKeys [4]uint64, edges []uint64) (bool, error) {
+	uvs := make([]uint64, 2*c.proofSize)
+	ndir := make([]int, 2)
+	var xor0, xor1 uint64
+
+	for n := 0; n < c.proofSize; n++ {
+		dir := int(edges[n] & 1)
+		if ndir[dir] >= c.proofSize/2 {
+			return false, ErrPowUnbalanced
+		} else if edges[n] > c.edgeMask {
+			return false, ErrPowTooBig
+		} else if n > 0 && edges[n] <= edges[n-1] {
+			return false, ErrPowTooSmall
+		}
+
+		edge := c.sipblock(siphashKeys, edges[n])
+		idx := 4*ndir[dir] + 2*dir
+		uvs[idx] = edge & c.nodeMask
+		xor0 ^= uvs[idx]
+		uvs[idx+1] = (edge >> 32) & c.nodeMask
+		xor1 ^= uvs[idx+1]
+		ndir[dir]++
+	}
+
+	if xor0|xor1 != 0 {
+		return false, ErrPowNotMatching
+	}
+
+	var i, j, n int
+	for {
+		j = i
+
+		// find the reverse direction edge endpoint identical to the one at i
+		for k := (i % 4) ^ 2; k < 2*c.proofSize; k += 4 {
+			if uvs[k] == uvs[i] {
+				if j != i {
+					return false, ErrPowBranch
+				}
+
+				j = k
+			}
+		}
+
+		if j == i {
+			return false, ErrPowDeadEnd
+		}
+
+		i = j ^ 1
+		n++
+
+		if i == 0 {
+			break
+		}
+	}
+
+	if n != c.proofSize {
+		return false, ErrPowShortCycle
+	}
+
+	return true, nil
+}
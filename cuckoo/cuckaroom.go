@@ -0,0 +1,61 @@
+// Copyright (c) 2013-2020 John Tromp
+
+package cuckoo
+
+// cuckaroom verifies a cycle on the Cuckaroom graph, a directed graph where
+// each edge goes from its first node to its second node (Grin's secondary
+// proof of work from 524160 to 786239).
+func (c *Client) cuckaroom(siphashKeys [4]uint64, edges []uint64) (bool, error) {
+	from := make([]uint64, c.proofSize)
+	to := make([]uint64, c.proofSize)
+	visited := make([]bool, c.proofSize)
+	var xorFrom, xorTo uint64
+
+	for n := 0; n < c.proofSize; n++ {
+		if edges[n] > c.edgeMask {
+			return false, ErrPowTooBig
+		} else if n > 0 && edges[n] <= edges[n-1] {
+			return false, ErrPowTooSmall
+		}
+
+		edge := c.sipblock(siphashKeys, edges[n])
+		from[n] = edge & c.nodeMask
+		xorFrom ^= from[n]
+		to[n] = (edge >> 32) & c.nodeMask
+		xorTo ^= to[n]
+	}
+
+	if xorFrom != xorTo {
+		return false, ErrPowNotMatching
+	}
+
+	var i, n int
+	for {
+		if visited[i] {
+			return false, ErrPowBranch
+		}
+		visited[i] = true
+
+		// find the outgoing edge meeting the incoming edge i
+		next := 0
+		for from[next] != to[i] {
+			next++
+			if next == c.proofSize {
+				return false, ErrPowDeadEnd
+			}
+		}
+
+		i = next
+		n++
+
+		if i == 0 {
+			break
+		}
+	}
+
+	if n != c.proofSize {
+		return false, ErrPowShortCycle
+	}
+
+	return true, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2013-2020 John Tromp
+
+package cuckoo
+
+// cuckarooz verifies a cycle on the Cuckarooz graph, where both endpoints of
+// an edge share a single partition of nodes with one more bit than an edge
+// (Grin's secondary proof of work from 786240).
+func (c *Client) cuckarooz(siphashKeys [4]uint64, edges []uint64) (bool, error) {
+	uvs := make([]uint64, 2*c.proofSize)
+	var xoruv uint64
+
+	for n := 0; n < c.proofSize; n++ {
+		if edges[n] > c.edgeMask {
+			return false, ErrPowTooBig
+		} else if n > 0 && edges[n] <= edges[n-1] {
+			return false, ErrPowTooSmall
+		}
+
+		edge := c.sipblock(siphashKeys, edges[n])
+		uvs[2*n] = edge & c.nodeMask
+		xoruv ^= uvs[2*n]
+		uvs[2*n+1] = (edge >> 32) & c.nodeMask
+		xoruv ^= uvs[2*n+1]
+	}
+
+	if xoruv != 0 {
+		return false, ErrPowNotMatching
+	}
+
+	var i, j, n int
+	for {
+		j = i
+		k := j
+
+		for {
+			k = (k + 1) % (2 * c.proofSize)
+			if k == i {
+				break
+			}
+
+			// find the other edge endpoint identical to the one at i
+			if uvs[k] == uvs[i] {
+				if j != i {
+					return false, ErrPowBranch
+				}
+
+				j = k
+			}
+		}
+
+		if j == i {
+			return false, ErrPowDeadEnd
+		}
+
+		i = j ^ 1
+		n++
+
+		if i == 0 {
+			break
+		}
+	}
+
+	if n != c.proofSize {
+		return false, ErrPowShortCycle
+	}
+
+	return true, nil
+}
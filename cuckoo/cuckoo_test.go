@@ -1,9 +1,15 @@
 package cuckoo
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/sencha-dev/powkit/internal/common/testutil"
+	"github.com/sencha-dev/powkit/internal/crypto"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
 func TestAeternity(t *testing.T) {
@@ -53,6 +59,17 @@ func TestAeternity(t *testing.T) {
 		} else if !valid {
 			t.Errorf("failed on %d: invalid solution", i)
 		}
+
+		// unsorted and duplicate edges must be rejected
+		unsorted := append([]uint64{}, tt.sols...)
+		unsorted[0], unsorted[1] = unsorted[1], unsorted[0]
+		duplicate := append([]uint64{}, tt.sols...)
+		duplicate[1] = duplicate[0]
+		for _, sols := range [][]uint64{unsorted, duplicate} {
+			if _, err := NewAeternity().Verify(tt.header, sols); err != ErrPowTooSmall {
+				t.Errorf("failed on %d: have %v, want %v", i, err, ErrPowTooSmall)
+			}
+		}
 	}
 }
 
@@ -103,5 +120,313 @@ func TestCortex(t *testing.T) {
 		} else if !valid {
 			t.Errorf("failed on %d: invalid solution", i)
 		}
+
+		// unsorted and duplicate edges must be rejected
+		unsorted := append([]uint64{}, tt.sols...)
+		unsorted[0], unsorted[1] = unsorted[1], unsorted[0]
+		duplicate := append([]uint64{}, tt.sols...)
+		duplicate[1] = duplicate[0]
+		for _, sols := range [][]uint64{unsorted, duplicate} {
+			if _, err := NewCortex().Verify(tt.header, sols); err != ErrPowTooSmall {
+				t.Errorf("failed on %d: have %v, want %v", i, err, ErrPowTooSmall)
+			}
+		}
+	}
+}
+
+// @TODO: add Grin mainnet C31 and C32 block proofs, the vectors below are
+// solutions of zero headers and C32 has no valid one yet.
+func TestGrin(t *testing.T) {
+	tests := []struct {
+		client *Client
+		header []byte
+		sols   []uint64
+	}{
+		{
+			client: NewCuckatoo(29, 42, crypto.SipNode24, nil),
+			header: testutil.MustDecodeHex("0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000014000000"),
+			sols: []uint64{
+				0x0048a9e2, 0x009cf043, 0x0155ca30, 0x018f4783, 0x0248f86c, 0x02629a64, 0x05bad752, 0x072e3569,
+				0x093db760, 0x097d3b37, 0x09e05670, 0x0a315d5a, 0x0a3571a1, 0x0a48db46, 0x0a7796b6, 0x0ac43611,
+				0x0b64912f, 0x0bb6c71e, 0x0bcc8be1, 0x0c38a43a, 0x0d4faa99, 0x0e018a66, 0x0e37e49c, 0x0fa975fa,
+				0x11786035, 0x1243b60a, 0x12892da0, 0x141b5453, 0x1483c3a0, 0x1505525e, 0x1607352c, 0x16181fe3,
+				0x17e3a1da, 0x180b651e, 0x1899d678, 0x1931b0bb, 0x19606448, 0x1b041655, 0x1b2c20ad, 0x1bd7a83c,
+				0x1c05d5b0, 0x1c0b9caa,
+			},
+		},
+		{
+			client: NewGrinC31(),
+			header: testutil.MustDecodeHex("0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000063000000"),
+			sols: []uint64{
+				0x01128e07, 0x0c181131, 0x110fad36, 0x1135ddee, 0x1669c7d3, 0x1931e6ea, 0x1c0005f3, 0x1dd6ecca,
+				0x1e29ce7e, 0x209736fc, 0x2692bf1a, 0x27b85aa9, 0x29bb7693, 0x2dc2a047, 0x2e28650a, 0x2f381195,
+				0x350eb3f9, 0x3beed728, 0x3e861cbc, 0x41448cc1, 0x41f08f6d, 0x42fbc48a, 0x4383ab31, 0x4389c61f,
+				0x4540a5ce, 0x49a17405, 0x50372ded, 0x512f0db0, 0x588b6288, 0x5a36aa46, 0x5c29e1fe, 0x6118ab16,
+				0x634705b5, 0x6633d190, 0x6683782f, 0x6728b6e1, 0x67adfb45, 0x68ae2306, 0x6d60f5e1, 0x78af3c4f,
+				0x7dde51ab, 0x7faced21,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		valid, err := tt.client.Verify(tt.header, tt.sols)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if !valid {
+			t.Errorf("failed on %d: invalid solution", i)
+		}
+
+		// the same solution must not verify on the larger graph
+		invalid, err := NewGrinC32().Verify(tt.header, tt.sols)
+		if err == nil || invalid {
+			t.Errorf("failed on %d: expected invalid solution on C32", i)
+		}
+	}
+}
+
+func TestGrinEdgeBits(t *testing.T) {
+	tests := []struct {
+		client *Client
+		last   uint64
+		err    error
+	}{
+		{client: NewGrinC31(), last: 1<<31 - 1, err: ErrPowNotMatching},
+		{client: NewGrinC31(), last: 1 << 31, err: ErrPowTooBig},
+		{client: NewGrinC32(), last: 1 << 31, err: ErrPowNotMatching},
+		{client: NewGrinC32(), last: 1<<32 - 1, err: ErrPowNotMatching},
+		{client: NewGrinC32(), last: 1 << 32, err: ErrPowTooBig},
+	}
+
+	// an ascending proof whose last edge is checked against the graph size
+	sols := make([]uint64, 42)
+	for i := range sols {
+		sols[i] = uint64(i)
+	}
+
+	for i, tt := range tests {
+		sols[len(sols)-1] = tt.last
+		if _, err := tt.client.verify([4]uint64{}, sols); err != tt.err {
+			t.Errorf("failed on %d: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// grinSecondaryTests are the 19 and 29 edge bits vectors of the Grin secondary
+// proof of work variants, shared by the verifier and solver tests.
+var grinSecondaryTests = []struct {
+	client *Client
+	keys   [4]uint64
+	sols   []uint64
+}{
+	{
+		client: NewCuckaroo(19, 42, nil, crypto.SipBlock24),
+		keys:   [4]uint64{0x23796193872092ea, 0xf1017d8a68c4b745, 0xd312bd53d2cd307b, 0x840acce5833ddc52},
+		sols: []uint64{
+			0x045e9, 0x06a59, 0x0f1ad, 0x10ef7, 0x129e8, 0x13e58, 0x17936, 0x19f7f, 0x208df, 0x23704,
+			0x24564, 0x27e64, 0x2b828, 0x2bb41, 0x2ffc0, 0x304c5, 0x31f2a, 0x347de, 0x39686, 0x3ab6c,
+			0x429ad, 0x45254, 0x49200, 0x4f8f8, 0x5697f, 0x57ad1, 0x5dd47, 0x607f8, 0x66199, 0x686c7,
+			0x6d5f3, 0x6da7a, 0x6dbdf, 0x6f6bf, 0x6ffbb, 0x7580e, 0x78594, 0x785ac, 0x78b1d, 0x7b80d,
+			0x7c11c, 0x7da35,
+		},
+	},
+	{
+		client: NewCuckarood(19, 42, nil, crypto.SipBlock24Rot25),
+		keys:   [4]uint64{0x89f81d7da5e674df, 0x7586b93105a5fd13, 0x6fbe212dd4e8c001, 0x8800c93a8431f938},
+		sols: []uint64{
+			0x00a00, 0x03ffb, 0x0a474, 0x0dc27, 0x182e6, 0x242cc, 0x24de4, 0x270a2, 0x28356, 0x2951f,
+			0x2a6ae, 0x2c889, 0x355c7, 0x3863b, 0x3bd7e, 0x3cdbc, 0x3ff95, 0x430b6, 0x4ba1a, 0x4bd7e,
+			0x4c59f, 0x4f76d, 0x52064, 0x5378c, 0x540a3, 0x5af6b, 0x5b041, 0x5e9d3, 0x64ec7, 0x6564b,
+			0x66763, 0x66899, 0x66e80, 0x68e4e, 0x69133, 0x6b20a, 0x6c2d7, 0x6fd3b, 0x79a8a, 0x79e29,
+			0x7ae52, 0x7defe,
+		},
+	},
+	{
+		client: NewCuckaroom(19, 42, nil, crypto.SipBlock24XorAll),
+		keys:   [4]uint64{0xdb7896f799c76dab, 0x352e8bf25df7a723, 0xf0aa29cbb1150ea6, 0x3206c2759f41cbd5},
+		sols: []uint64{
+			0x0413c, 0x05121, 0x0546e, 0x1293a, 0x1dd27, 0x1e13e, 0x1e1d2, 0x22870, 0x24642, 0x24833,
+			0x29190, 0x2a732, 0x2ccf6, 0x302cf, 0x32d9a, 0x33700, 0x33a20, 0x351d9, 0x3554b, 0x35a70,
+			0x376c1, 0x398c6, 0x3f404, 0x3ff0c, 0x48b26, 0x49a03, 0x4c555, 0x4dcda, 0x4dfcd, 0x4fbb6,
+			0x50275, 0x584a8, 0x5da0d, 0x5dbf1, 0x6038f, 0x66540, 0x72bbd, 0x77323, 0x77424, 0x77a14,
+			0x77dc9, 0x7d9dc,
+		},
+	},
+	{
+		client: NewGrinCuckaroom29(),
+		keys:   [4]uint64{0xe4b4a751f2eac47d, 0x3115d47edfb69267, 0x87de84146d9d609e, 0x7deb20eab6d976a1},
+		sols: []uint64{
+			0x04acd28, 0x29ccf71, 0x2a5572b, 0x2f31c2c, 0x2f60c37, 0x317fe1d, 0x32f6d4c, 0x3f51227,
+			0x45ee1dc, 0x535eeb8, 0x5e135d5, 0x6184e3d, 0x6b1b8e0, 0x6f857a9, 0x8916a0f, 0x9beb5f8,
+			0xa3c8dc9, 0xa886d94, 0xaab6a57, 0xd6df8f8, 0xe4d630f, 0xe6ae422, 0xea2d658, 0xf7f369b,
+			0x10c465d8, 0x1130471e, 0x12049efb, 0x12f43bc5, 0x15b493a6, 0x16899354, 0x1915dfca, 0x195c3dac,
+			0x19b09ab6, 0x1a1a8ed7, 0x1bba748f, 0x1bdbf777, 0x1c806542, 0x1d201b53, 0x1d9e6af7, 0x1e99885e,
+			0x1f255834, 0x1f9c383b,
+		},
+	},
+	{
+		client: NewCuckarooz(19, 42, nil, crypto.SipBlock24XorAll),
+		keys:   [4]uint64{0xd129f63fba4d9a85, 0x457dcb3666c5e09c, 0x045247a2e2ee75f7, 0x1a0f2e1bcb9d93ff},
+		sols: []uint64{
+			0x033b6, 0x0487b, 0x088b7, 0x10bf6, 0x15144, 0x17cb7, 0x22621, 0x2358e, 0x23775, 0x24fb3,
+			0x26b8a, 0x2876c, 0x2973e, 0x2f4ba, 0x30a62, 0x3a36b, 0x3ba5d, 0x3be67, 0x3ec56, 0x43141,
+			0x4b9c5, 0x4fa06, 0x51a5c, 0x523e5, 0x53d08, 0x57d34, 0x5c2de, 0x60bba, 0x62509, 0x64d69,
+			0x6803f, 0x68af4, 0x6bd52, 0x6f041, 0x6f900, 0x70051, 0x7097d, 0x735e8, 0x742c2, 0x79ae5,
+			0x7f64d, 0x7fd49,
+		},
+	},
+}
+
+func TestGrinSecondary(t *testing.T) {
+	for i, tt := range grinSecondaryTests {
+		valid, err := tt.client.verify(tt.keys, tt.sols)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if !valid {
+			t.Errorf("failed on %d: invalid solution", i)
+		}
+
+		// an unsorted solution must be rejected
+		sols := append([]uint64{}, tt.sols...)
+		sols[0], sols[1] = sols[1], sols[0]
+		if _, err := tt.client.verify(tt.keys, sols); err != ErrPowTooSmall {
+			t.Errorf("failed on %d: have %v, want %v", i, err, ErrPowTooSmall)
+		}
+	}
+}
+
+func TestGrinSecondaryHeight(t *testing.T) {
+	tests := []struct {
+		height  uint64
+		variant CuckooVariant
+	}{
+		{height: 0, variant: Cuckaroo},
+		{height: 262079, variant: Cuckaroo},
+		{height: 262080, variant: Cuckarood},
+		{height: 524159, variant: Cuckarood},
+		{height: 524160, variant: Cuckaroom},
+		{height: 786239, variant: Cuckaroom},
+		{height: 786240, variant: Cuckarooz},
+	}
+
+	for i, tt := range tests {
+		variant := NewGrinSecondary(tt.height).variant
+		if variant != tt.variant {
+			t.Errorf("failed on %d: variant mismatch: have %d, want %d", i, variant, tt.variant)
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		client *Client
+		nonce  uint32
+	}{
+		{
+			client: NewCuckoo(16, 8, crypto.SipNode24, nil),
+			nonce:  10,
+		},
+		{
+			client: NewCuckatoo(16, 8, crypto.SipNode24, nil),
+			nonce:  15,
+		},
+		{
+			client: NewCuckatoo(20, 42, crypto.SipNode24, nil),
+			nonce:  32,
+		},
+	}
+
+	for i, tt := range tests {
+		header := make([]byte, 80)
+		binary.LittleEndian.PutUint32(header[76:], tt.nonce)
+
+		sols, err := tt.client.Solve(header)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		} else if len(sols) == 0 {
+			t.Errorf("failed on %d: no solution found", i)
+		}
+
+		for _, sol := range sols {
+			valid, err := tt.client.Verify(header, sol)
+			if err != nil {
+				t.Errorf("failed on %d: %v", i, err)
+			} else if !valid {
+				t.Errorf("failed on %d: invalid solution", i)
+			}
+		}
+	}
+
+	invalid := []*Client{
+		NewGrinC31(),
+		NewCuckoo(16, 8, nil, nil),
+		NewCuckaroo(16, 8, nil, nil),
+	}
+
+	for i, client := range invalid {
+		if _, err := client.Solve(make([]byte, 80)); !errors.Is(err, powerr.ErrInvalidInput) {
+			t.Errorf("failed on invalid %d: expected invalid input error, have %v", i, err)
+		}
+	}
+
+	// the known solutions of the small secondary graphs must be found
+	for i, tt := range grinSecondaryTests {
+		if tt.client.edgeBits > 24 {
+			continue
+		}
+
+		var found bool
+		for _, sol := range tt.client.solve(tt.keys) {
+			found = found || reflect.DeepEqual(sol, tt.sols)
+		}
+
+		if !found {
+			t.Errorf("failed on secondary %d: solution not found", i)
+		}
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	client := NewCuckoo(16, 8, crypto.SipNode24, nil)
+	client.SetWorkers(2)
+
+	header := make([]byte, 80)
+	binary.LittleEndian.PutUint32(header[76:], 10)
+
+	sols, err := client.Solve(header)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(sols) == 0 {
+		t.Fatalf("no solution found")
+	}
+
+	otherHeader := make([]byte, 80)
+	binary.LittleEndian.PutUint32(otherHeader[76:], 11)
+
+	unordered := append([]uint64{}, sols[0]...)
+	unordered[0], unordered[1] = unordered[1], unordered[0]
+
+	var jobs []VerifyJob
+	for _, sol := range sols {
+		jobs = append(jobs, VerifyJob{Header: header, Sols: sol})
+	}
+	jobs = append(jobs,
+		VerifyJob{Header: otherHeader, Sols: sols[0]},
+		VerifyJob{Header: header, Sols: unordered},
+		VerifyJob{Header: header, Sols: sols[0][1:]},
+	)
+
+	results, err := client.VerifyBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, job := range jobs {
+		valid, err := client.Verify(job.Header, job.Sols)
+		if results[i].Valid != valid {
+			t.Errorf("failed on %d: valid mismatch: have %t, want %t", i, results[i].Valid, valid)
+		} else if (err != nil) != (results[i].Err != nil) {
+			t.Errorf("failed on %d: error mismatch: have %v, want %v", i, results[i].Err, err)
+		}
 	}
 }
@@ -1,14 +1,16 @@
 package cuckoo
 
 import (
-	"fmt"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
+// Verification errors, all matching powerr.ErrInvalidSolution.
 var (
-	ErrPowTooBig      = fmt.Errorf("pow too big")
-	ErrPowTooSmall    = fmt.Errorf("pow too small")
-	ErrPowNotMatching = fmt.Errorf("pow not matching")
-	ErrPowBranch      = fmt.Errorf("pow branch")
-	ErrPowDeadEnd     = fmt.Errorf("pow dead end")
-	ErrPowShortCycle  = fmt.Errorf("pow short cycle")
+	ErrPowTooBig      = &powerr.SolutionError{Reason: powerr.ReasonPowTooBig}
+	ErrPowTooSmall    = &powerr.SolutionError{Reason: powerr.ReasonPowTooSmall}
+	ErrPowNotMatching = &powerr.SolutionError{Reason: powerr.ReasonPowNotMatching}
+	ErrPowBranch      = &powerr.SolutionError{Reason: powerr.ReasonPowBranch}
+	ErrPowDeadEnd     = &powerr.SolutionError{Reason: powerr.ReasonPowDeadEnd}
+	ErrPowShortCycle  = &powerr.SolutionError{Reason: powerr.ReasonPowShortCycle}
+	ErrPowUnbalanced  = &powerr.SolutionError{Reason: powerr.ReasonPowUnbalanced}
 )
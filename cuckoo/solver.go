@@ -0,0 +1,269 @@
+// Copyright (c) 2013-2020 John Tromp
+
+package cuckoo
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+const maxSolverEdgeBits = 30
+
+// graph holds the endpoints of every edge as node ids in a single node space
+// (bipartite graphs keep their partition in the lowest bit of the id). For
+// the variants where a cycle must pair two different kinds of endpoints at
+// each node, the kind of each endpoint is kept in classes (bit 0 for u, bit
+// 1 for v).
+type graph struct {
+	directed bool
+	us       []uint32
+	vs       []uint32
+	classes  []uint8
+}
+
+// class returns the kind of the endpoint of edge at node.
+func (g *graph) class(edge, node uint32) uint8 {
+	if g.us[edge] == node {
+		return g.classes[edge] & 1
+	}
+
+	return g.classes[edge] >> 1
+}
+
+// other returns the endpoint of edge that is not node.
+func (g *graph) other(edge, node uint32) uint32 {
+	if g.us[edge] == node {
+		return g.vs[edge]
+	}
+
+	return g.us[edge]
+}
+
+// endpoints returns the two nodes of an edge and their kinds.
+//   - Cuckatoo merges the nodes differing only in the lowest bit, which
+//     becomes the kind, since an edge connects to the node x^1 of the previous one.
+//   - Cuckarood alternates between even and odd edges.
+//   - Cuckaroom goes from the u endpoint of an edge to the v endpoint of the next one.
+func (c *Client) endpoints(siphashKeys [4]uint64, nonce uint64) (uint64, uint64, uint8) {
+	switch c.variant {
+	case Cuckoo:
+		u := c.sipnode(c.edgeMask, siphashKeys, nonce, 0)
+		v := c.sipnode(c.edgeMask, siphashKeys, nonce, 1)
+		return u << 1, v<<1 | 1, 0
+	case Cuckatoo:
+		u := c.sipnode(c.edgeMask, siphashKeys, nonce, 0)
+		v := c.sipnode(c.edgeMask, siphashKeys, nonce, 1)
+		return u &^ 1, v | 1, uint8(u&1 | (v&1)<<1)
+	case Cuckarood:
+		edge := c.sipblock(siphashKeys, nonce)
+		dir := uint8(nonce & 1)
+		return (edge & c.nodeMask) << 1, ((edge>>32)&c.nodeMask)<<1 | 1, dir | dir<<1
+	case Cuckaroom:
+		edge := c.sipblock(siphashKeys, nonce)
+		return edge & c.nodeMask, (edge >> 32) & c.nodeMask, 2
+	case Cuckarooz:
+		edge := c.sipblock(siphashKeys, nonce)
+		return edge & c.nodeMask, (edge >> 32) & c.nodeMask, 0
+	default:
+		edge := c.sipblock(siphashKeys, nonce)
+		return (edge & c.edgeMask) << 1, ((edge>>32)&c.edgeMask)<<1 | 1, 0
+	}
+}
+
+// Solve finds all the cycles of the client's proof size in the graph of the
+// header and returns them as sorted edge nonces accepted by Verify. It keeps
+// every edge and a counter for each node in memory, so it is only practical
+// for small graphs (up to around 24 edge bits).
+func (c *Client) Solve(header []byte) ([][]uint64, error) {
+	if c.edgeBits > maxSolverEdgeBits {
+		return nil, &powerr.InputError{Field: "edge bits", Reason: fmt.Sprintf("must be at most %d for the solver", maxSolverEdgeBits)}
+	}
+
+	switch c.variant {
+	case Cuckoo, Cuckatoo:
+		if c.sipnode == nil {
+			return nil, &powerr.InputError{Field: "sipnode", Reason: "must be set"}
+		}
+	default:
+		if c.sipblock == nil {
+			return nil, &powerr.InputError{Field: "sipblock", Reason: "must be set"}
+		}
+	}
+
+	return c.solve(siphashKeys(header)), nil
+}
+
+func (c *Client) solve(siphashKeys [4]uint64) [][]uint64 {
+	g := c.generateGraph(siphashKeys)
+	alive := c.trimEdges(g)
+
+	var sols [][]uint64
+	seen := make(map[string]bool)
+	for _, cycle := range c.findCycles(g, alive) {
+		sol := make([]uint64, len(cycle))
+		for i, edge := range cycle {
+			sol[i] = uint64(edge)
+		}
+		sort.Slice(sol, func(i, j int) bool { return sol[i] < sol[j] })
+
+		// every cycle is found once in each direction
+		key := fmt.Sprint(sol)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if valid, _ := c.verify(siphashKeys, sol); valid {
+			sols = append(sols, sol)
+		}
+	}
+
+	return sols
+}
+
+// generateGraph computes the endpoints of every edge on all cores.
+func (c *Client) generateGraph(siphashKeys [4]uint64) *graph {
+	numEdges := int(c.edgeMask) + 1
+	g := &graph{
+		directed: c.variant == Cuckatoo || c.variant == Cuckarood || c.variant == Cuckaroom,
+		us:       make([]uint32, numEdges),
+		vs:       make([]uint32, numEdges),
+		classes:  make([]uint8, numEdges),
+	}
+
+	threads := runtime.NumCPU()
+	batch := (numEdges + threads - 1) / threads
+
+	var pend sync.WaitGroup
+	pend.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func(id int) {
+			defer pend.Done()
+
+			first := id * batch
+			limit := first + batch
+			if limit > numEdges {
+				limit = numEdges
+			}
+
+			for nonce := first; nonce < limit; nonce++ {
+				u, v, classes := c.endpoints(siphashKeys, uint64(nonce))
+				g.us[nonce], g.vs[nonce], g.classes[nonce] = uint32(u), uint32(v), classes
+			}
+		}(i)
+	}
+
+	pend.Wait()
+
+	return g
+}
+
+// trimEdges repeatedly removes the edges with an endpoint that cannot be
+// part of a cycle and returns the remaining edges. Nodes need two edges, or
+// for directed graphs an edge of each kind.
+func (c *Client) trimEdges(g *graph) []uint32 {
+	numNodes := 2 * (c.nodeMask + 1)
+	degrees := make([]uint8, numNodes)
+
+	// directed graphs set a bit per kind, others count up to two edges
+	mark := func(node uint32, class uint8) {
+		if g.directed {
+			degrees[node] |= 1 << class
+		} else if degrees[node] < 2 {
+			degrees[node]++
+		}
+	}
+
+	threshold := uint8(2)
+	if g.directed {
+		threshold = 3
+	}
+
+	alive := make([]uint32, 0, len(g.us))
+	for edge := range g.us {
+		// self loops are never part of a cycle of the proof size
+		if g.us[edge] != g.vs[edge] {
+			alive = append(alive, uint32(edge))
+		}
+	}
+
+	for {
+		for i := range degrees {
+			degrees[i] = 0
+		}
+
+		for _, edge := range alive {
+			mark(g.us[edge], g.classes[edge]&1)
+			mark(g.vs[edge], g.classes[edge]>>1)
+		}
+
+		remaining := alive[:0]
+		for _, edge := range alive {
+			if degrees[g.us[edge]] >= threshold && degrees[g.vs[edge]] >= threshold {
+				remaining = append(remaining, edge)
+			}
+		}
+
+		if len(remaining) == len(alive) {
+			return remaining
+		}
+		alive = remaining
+	}
+}
+
+// findCycles returns every simple cycle of the proof size in the trimmed
+// graph, each starting from its smallest edge.
+func (c *Client) findCycles(g *graph, alive []uint32) [][]uint32 {
+	adjacency := make(map[uint32][]uint32)
+	for _, edge := range alive {
+		adjacency[g.us[edge]] = append(adjacency[g.us[edge]], edge)
+		adjacency[g.vs[edge]] = append(adjacency[g.vs[edge]], edge)
+	}
+
+	var cycles [][]uint32
+	path := make([]uint32, 0, c.proofSize)
+	visited := make(map[uint32]bool)
+
+	var walk func(start, node, target uint32)
+	walk = func(start, node, target uint32) {
+		last := path[len(path)-1]
+		if node == target {
+			if len(path) == c.proofSize && (!g.directed || g.class(last, node) != g.class(start, node)) {
+				cycles = append(cycles, append([]uint32{}, path...))
+			}
+			return
+		} else if len(path) == c.proofSize {
+			return
+		}
+
+		visited[node] = true
+		for _, edge := range adjacency[node] {
+			if edge <= start || edge == last {
+				continue
+			} else if g.directed && g.class(edge, node) == g.class(last, node) {
+				continue
+			}
+
+			next := g.other(edge, node)
+			if visited[next] {
+				continue
+			}
+
+			path = append(path, edge)
+			walk(start, next, target)
+			path = path[:len(path)-1]
+		}
+		delete(visited, node)
+	}
+
+	for _, start := range alive {
+		path = append(path[:0], start)
+		walk(start, g.vs[start], g.us[start])
+	}
+
+	return cycles
+}
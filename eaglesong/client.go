@@ -1,11 +1,18 @@
 package eaglesong
 
+import (
+	"context"
+
+	"github.com/sencha-dev/powkit/internal/batch"
+)
+
 type Client struct {
 	rounds   int
 	capacity int
 	rate     int
 	length   int
 	delim    byte
+	workers  int
 }
 
 func New(rounds, capacity, rate, length int, delim byte) *Client {
@@ -27,3 +34,27 @@ func NewNervos() *Client {
 func (c *Client) Compute(input []byte) []byte {
 	return eaglesong(c.rounds, c.capacity, c.rate, c.delim, input)
 }
+
+// SetWorkers sets the number of goroutines ComputeBatch runs on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
+// ComputeBatch computes the digest of every input across the workers. The
+// digests are in the order of the inputs, the error is only returned if ctx
+// is done before all of them are computed.
+func (c *Client) ComputeBatch(ctx context.Context, inputs [][]byte) ([][]byte, error) {
+	digests := make([][]byte, len(inputs))
+	newWorker := func() func(int) {
+		return func(i int) {
+			digests[i] = c.Compute(inputs[i])
+		}
+	}
+
+	if err := batch.Run(ctx, len(inputs), c.workers, newWorker); err != nil {
+		return nil, err
+	}
+
+	return digests, nil
+}
@@ -2,6 +2,7 @@ package eaglesong
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/sencha-dev/powkit/internal/common/testutil"
@@ -25,3 +26,25 @@ func TestEaglesong(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeBatch(t *testing.T) {
+	var inputs [][]byte
+	for i := 0; i < 100; i++ {
+		inputs = append(inputs, bytes.Repeat([]byte{byte(i)}, i))
+	}
+
+	client := NewNervos()
+	client.SetWorkers(3)
+
+	digests, err := client.ComputeBatch(context.Background(), inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, input := range inputs {
+		digest := client.Compute(input)
+		if bytes.Compare(digests[i], digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, digests[i], digest)
+		}
+	}
+}
@@ -1,10 +1,26 @@
 package equihash
 
+import (
+	"context"
+
+	"github.com/sencha-dev/powkit/internal/batch"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// VerifyJob is a single solution to verify with VerifyBatch.
+type VerifyJob = batch.VerifyJob
+
+// VerifyResult is the outcome of a VerifyJob.
+type VerifyResult = batch.VerifyResult
+
 type Client struct {
 	n        uint32
 	k        uint32
 	personal []byte
 	twist    bool
+
+	memoryBudget uint64
+	workers      int
 }
 
 func New(n, k uint32, personal string, twist bool) *Client {
@@ -38,6 +54,96 @@ func NewAion() *Client {
 	return New(210, 9, "AION0PoW", false)
 }
 
+// SetMemoryBudget limits the memory used by the solver to roughly the given
+// number of bytes, Solve failing once it is exceeded. Zero means no limit.
+func (c *Client) SetMemoryBudget(bytes uint64) {
+	c.memoryBudget = bytes
+}
+
+// SetWorkers sets the number of goroutines VerifyBatch runs on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
 func (c *Client) Verify(header, soln []byte) (bool, error) {
 	return verify(c.n, c.k, c.personal, header, soln, c.twist)
 }
+
+// VerifyIndices verifies a solution given as its expanded list of indices.
+func (c *Client) VerifyIndices(header []byte, indices []uint32) (bool, error) {
+	soln, err := IndicesToMinimal(c.n, c.k, indices)
+	if err != nil {
+		return false, err
+	}
+
+	return verify(c.n, c.k, c.personal, header, soln, c.twist)
+}
+
+// SolutionSize returns the length of a minimal encoded solution.
+func (c *Client) SolutionSize() int {
+	return MinimalSize(c.n, c.k)
+}
+
+// ParseSolution returns the minimal encoded solution of buf, which is either
+// already minimal encoded or prefixed with its CompactSize length.
+func (c *Client) ParseSolution(buf []byte) ([]byte, error) {
+	if len(buf) == c.SolutionSize() {
+		return buf, nil
+	}
+
+	soln, err := SolutionFromCompactSize(buf)
+	if err != nil {
+		return nil, err
+	} else if len(soln) != c.SolutionSize() {
+		return nil, &powerr.LengthError{Field: "soln", Want: c.SolutionSize(), Have: len(soln)}
+	}
+
+	return soln, nil
+}
+
+// Solve runs Wagner's algorithm over the header (including the nonce) and
+// returns every solution found, minimal encoded. All 2^(n/(k+1)+1) initial
+// rows are kept in memory, so it is only practical for small parameters
+// such as the ones used on test networks.
+func (c *Client) Solve(header []byte) ([][]byte, error) {
+	return c.SolveContext(context.Background(), header)
+}
+
+// SolveContext is like Solve but stops early with the context's error
+// once the context is cancelled.
+func (c *Client) SolveContext(ctx context.Context, header []byte) ([][]byte, error) {
+	if collisionBitLength(c.n, c.k)+1 > wordSize-1 {
+		return nil, &powerr.InputError{Field: "n, k", Reason: "are invalid parameters"}
+	}
+
+	s := &solver{
+		n:             c.n,
+		k:             c.k,
+		personal:      c.personal,
+		personalState: newPersonalState(c.n, c.k, c.personal),
+		twist:         c.twist,
+		budget:        c.memoryBudget,
+	}
+
+	return s.solve(ctx, header)
+}
+
+// VerifyBatch verifies every job across the workers. The results are in the
+// order of the jobs, the error is only returned if ctx is done before all of
+// them are verified.
+func (c *Client) VerifyBatch(ctx context.Context, jobs []VerifyJob) ([]VerifyResult, error) {
+	results := make([]VerifyResult, len(jobs))
+	newWorker := func() func(int) {
+		return func(i int) {
+			valid, err := c.Verify(jobs[i].Header, jobs[i].Soln)
+			results[i] = VerifyResult{Valid: valid, Err: err}
+		}
+	}
+
+	if err := batch.Run(ctx, len(jobs), c.workers, newWorker); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,114 @@
+package equihash
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// IndicesFromMinimal expands a minimal encoded solution into its 2^k indices.
+func IndicesFromMinimal(n, k uint32, minimal []byte) ([]uint32, error) {
+	return indicesFromMinimal(n, k, minimal)
+}
+
+// IndicesToMinimal packs the 2^k indices of a solution into the minimal
+// encoding, each index using n/(k+1)+1 bits.
+func IndicesToMinimal(n, k uint32, indices []uint32) ([]byte, error) {
+	indexBitLen := collisionBitLength(n, k) + 1
+	if indexBitLen < wordSize {
+		for _, index := range indices {
+			if index>>indexBitLen != 0 {
+				return nil, &powerr.InputError{Field: "indices", Reason: fmt.Sprintf("index %d does not fit in %d bits", index, indexBitLen)}
+			}
+		}
+	}
+
+	return indicesToMinimal(n, k, indices)
+}
+
+// MinimalSize returns the length of a minimal encoded solution for n, k
+// (1344 bytes for ZCash, 52 bytes for Flux).
+func MinimalSize(n, k uint32) int {
+	return int((1 << k) * (collisionBitLength(n, k) + 1) / 8)
+}
+
+// ReadCompactSize parses the Bitcoin CompactSize integer at the start of buf,
+// returning its value and the number of bytes it was encoded with. Non
+// canonical encodings are rejected, as they are by zcashd.
+func ReadCompactSize(buf []byte) (uint64, int, error) {
+	if len(buf) < 1 {
+		return 0, 0, &powerr.InputError{Field: "compact size", Reason: "is empty"}
+	}
+
+	var size uint64
+	var width int
+	var min uint64
+	switch buf[0] {
+	case 0xfd:
+		width, min = 3, 0xfd
+		if len(buf) >= width {
+			size = uint64(binary.LittleEndian.Uint16(buf[1:]))
+		}
+	case 0xfe:
+		width, min = 5, 0x10000
+		if len(buf) >= width {
+			size = uint64(binary.LittleEndian.Uint32(buf[1:]))
+		}
+	case 0xff:
+		width, min = 9, 0x100000000
+		if len(buf) >= width {
+			size = binary.LittleEndian.Uint64(buf[1:])
+		}
+	default:
+		return uint64(buf[0]), 1, nil
+	}
+
+	if len(buf) < width {
+		return 0, 0, &powerr.LengthError{Field: "compact size", Want: width, Have: len(buf)}
+	} else if size < min {
+		return 0, 0, &powerr.InputError{Field: "compact size", Reason: "is not canonical"}
+	}
+
+	return size, width, nil
+}
+
+// AppendCompactSize appends the CompactSize encoding of size to buf.
+func AppendCompactSize(buf []byte, size uint64) []byte {
+	switch {
+	case size < 0xfd:
+		return append(buf, byte(size))
+	case size <= 0xffff:
+		buf = append(buf, 0xfd, 0, 0)
+		binary.LittleEndian.PutUint16(buf[len(buf)-2:], uint16(size))
+	case size <= 0xffffffff:
+		buf = append(buf, 0xfe, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(buf[len(buf)-4:], uint32(size))
+	default:
+		buf = append(buf, 0xff, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint64(buf[len(buf)-8:], size)
+	}
+
+	return buf
+}
+
+// SolutionFromCompactSize strips the CompactSize length prefix of a solution
+// as serialized in block headers, checking that it matches the solution length.
+func SolutionFromCompactSize(buf []byte) ([]byte, error) {
+	size, width, err := ReadCompactSize(buf)
+	if err != nil {
+		return nil, err
+	} else if uint64(len(buf)-width) != size {
+		return nil, &powerr.LengthError{Field: "soln", Want: int(size), Have: len(buf) - width}
+	}
+
+	return buf[width:], nil
+}
+
+// SolutionToCompactSize prefixes the solution with its CompactSize length,
+// as serialized in block headers.
+func SolutionToCompactSize(soln []byte) []byte {
+	buf := AppendCompactSize(make([]byte, 0, len(soln)+9), uint64(len(soln)))
+
+	return append(buf, soln...)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sencha-dev/powkit/internal/common/convutil"
 	"github.com/sencha-dev/powkit/internal/crypto"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
 const (
@@ -38,9 +39,9 @@ func hashLength(n, k uint32) uint32 {
 
 func expandArray(input []byte, bitLen, bytePad uint32) ([]byte, error) {
 	if bitLen < 8 {
-		return nil, fmt.Errorf("bitLen must be no less than 8")
+		return nil, &powerr.InputError{Field: "bitLen", Reason: "must be no less than 8"}
 	} else if wordSize < bitLen {
-		return nil, fmt.Errorf("bitLen must be no greater than %d", wordSize-7)
+		return nil, &powerr.InputError{Field: "bitLen", Reason: fmt.Sprintf("must be no greater than %d", wordSize)}
 	}
 
 	inputLen := uint32(len(input))
@@ -74,14 +75,49 @@ func expandArray(input []byte, bitLen, bytePad uint32) ([]byte, error) {
 	return output, nil
 }
 
+// compressArray is the inverse of expandArray, packing the lowest bitLen bits
+// of each (bitLen+7)/8 + bytePad byte wide big-endian input value.
+func compressArray(input []byte, bitLen, bytePad uint32) ([]byte, error) {
+	if bitLen < 8 {
+		return nil, &powerr.InputError{Field: "bitLen", Reason: "must be no less than 8"}
+	} else if wordSize < bitLen {
+		return nil, &powerr.InputError{Field: "bitLen", Reason: fmt.Sprintf("must be no greater than %d", wordSize)}
+	}
+
+	inputWidth := (bitLen+7)/8 + bytePad
+	outputLen := bitLen * uint32(len(input)) / (8 * inputWidth)
+	output := make([]byte, outputLen)
+	var bitLenMask uint64 = (1 << bitLen) - 1
+
+	var accBits, j uint32
+	var accValue uint64
+	for i := range output {
+		if accBits < 8 {
+			accValue = accValue << bitLen
+			for x := bytePad; x < inputWidth; x++ {
+				shift := 8 * (inputWidth - x - 1)
+				accValue |= (uint64(input[j+x]) & ((bitLenMask >> shift) & 0xFF)) << shift
+			}
+
+			j += inputWidth
+			accBits += bitLen
+		}
+
+		accBits -= 8
+		output[i] = uint8(accValue >> accBits)
+	}
+
+	return output, nil
+}
+
 func indicesFromMinimal(n, k uint32, minimal []byte) ([]uint32, error) {
 	cBitLen := collisionBitLength(n, k)
 	minimalLen := uint32(len(minimal))
 
 	if minimalLen != ((1<<k)*(cBitLen+1))/8 {
-		return nil, fmt.Errorf("invalid minimal for parameters")
+		return nil, &powerr.LengthError{Field: "soln", Want: MinimalSize(n, k), Have: len(minimal)}
 	} else if (((cBitLen + 1) + 7) / 8) > 4 {
-		return nil, fmt.Errorf("invalid n, k parameters")
+		return nil, &powerr.InputError{Field: "n, k", Reason: "are invalid parameters"}
 	}
 
 	bytePad := uint32Size - ((cBitLen+1)+7)/8
@@ -93,6 +129,21 @@ func indicesFromMinimal(n, k uint32, minimal []byte) ([]uint32, error) {
 	return convutil.BytesToUint32Array(indices, binary.BigEndian), nil
 }
 
+func indicesToMinimal(n, k uint32, indices []uint32) ([]byte, error) {
+	cBitLen := collisionBitLength(n, k)
+
+	if uint32(len(indices)) != 1<<k {
+		return nil, &powerr.LengthError{Field: "indices", Want: 1 << k, Have: len(indices), Unit: "indices"}
+	} else if (((cBitLen + 1) + 7) / 8) > 4 {
+		return nil, &powerr.InputError{Field: "n, k", Reason: "are invalid parameters"}
+	}
+
+	bytePad := uint32Size - ((cBitLen+1)+7)/8
+	expanded := convutil.Uint32ArrayToBytes(indices, binary.BigEndian)
+
+	return compressArray(expanded, cBitLen+1, bytePad)
+}
+
 func hashBlakeWithOffset(initialState, personalState []byte, offset, hashLength uint32) []byte {
 	newState := make([]byte, len(initialState)+4)
 	copy(newState, initialState)
@@ -174,20 +225,20 @@ func validateSubtrees(n, k uint32, a, b *node) error {
 	// check hasCollision
 	for i := uint32(0); i < collisionByteLength(n, k); i++ {
 		if a.hash[i] != b.hash[i] {
-			return fmt.Errorf("collision")
+			return &powerr.SolutionError{Reason: powerr.ReasonCollision}
 		}
 	}
 
 	// check indicesBefore
 	if b.indices[0] < a.indices[0] {
-		return fmt.Errorf("out of order")
+		return &powerr.SolutionError{Reason: powerr.ReasonOutOfOrder}
 	}
 
 	// check distinctIndices
 	for _, i := range a.indices {
 		for _, j := range b.indices {
 			if i == j {
-				return fmt.Errorf("duplicate indices")
+				return &powerr.SolutionError{Reason: powerr.ReasonDuplicateIndices}
 			}
 		}
 	}
@@ -195,12 +246,18 @@ func validateSubtrees(n, k uint32, a, b *node) error {
 	return nil
 }
 
-func verify(n, k uint32, personal, header, soln []byte, twist bool) (bool, error) {
+func newPersonalState(n, k uint32, personal []byte) []byte {
 	personalState := make([]byte, len(personal)+8)
 	copy(personalState, personal)
 	binary.LittleEndian.PutUint32(personalState[len(personal):], n)
 	binary.LittleEndian.PutUint32(personalState[len(personal)+4:], k)
 
+	return personalState
+}
+
+func verify(n, k uint32, personal, header, soln []byte, twist bool) (bool, error) {
+	personalState := newPersonalState(n, k, personal)
+
 	indices, err := indicesFromMinimal(n, k, soln)
 	if err != nil {
 		return false, err
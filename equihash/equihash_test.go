@@ -4,8 +4,13 @@ package equihash
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/sencha-dev/powkit/powerr"
 )
 
 func TestExpandArray(t *testing.T) {
@@ -169,6 +174,13 @@ func TestIndicesFromMinimal(t *testing.T) {
 			t.Errorf("failed on test %d: have %x want %x", i, actual, tt.expected)
 			continue
 		}
+
+		minimal, err := indicesToMinimal(tt.n, tt.k, actual)
+		if err != nil {
+			t.Errorf("failed on test %d: %v", i, err)
+		} else if bytes.Compare(minimal, tt.minimal) != 0 {
+			t.Errorf("failed on test %d: minimal mismatch: have %x want %x", i, minimal, tt.minimal)
+		}
 	}
 }
 
@@ -256,3 +268,281 @@ func TestVerifyFlux(t *testing.T) {
 		}
 	}
 }
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		client *Client
+		header []byte
+		soln   []byte
+	}{
+		{
+			client: New(96, 5, "ZcashPoW", false),
+			header: bytes.Join([][]byte{
+				[]byte("Equihash is an asymmetric PoW based on the Generalised Birthday problem."),
+				[]byte{
+					1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+					0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+				},
+			}, nil),
+			soln: []byte{
+				0x04, 0x6a, 0x8e, 0xd4, 0x51, 0xa2, 0x19, 0x73,
+				0x32, 0xe7, 0x1f, 0x39, 0xdb, 0x9c, 0x79, 0xfb,
+				0xf9, 0x3f, 0xc1, 0x44, 0x3d, 0xa5, 0x8f, 0xb3,
+				0x8d, 0x05, 0x99, 0x17, 0x21, 0x16, 0xd5, 0x55,
+				0xb1, 0xb2, 0x1f, 0x32, 0x70, 0x5c, 0xe9, 0x98,
+				0xf6, 0x0d, 0xa8, 0x52, 0xf7, 0x7f, 0x0e, 0x7f,
+				0x4d, 0x63, 0xfc, 0x2d, 0xd2, 0x30, 0xa3, 0xd9,
+				0x99, 0x53, 0xa0, 0x78, 0x7d, 0xfe, 0xfc, 0xab,
+				0x34, 0x1b, 0xde, 0xc8,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		sols, err := tt.client.Solve(tt.header)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		}
+
+		var found bool
+		for _, soln := range sols {
+			found = found || bytes.Equal(soln, tt.soln)
+		}
+
+		if !found {
+			t.Errorf("failed on %d: solution not found in %d solutions", i, len(sols))
+		}
+	}
+}
+
+func TestSolveTwist(t *testing.T) {
+	client := New(96, 5, "ZelProof", true)
+	header := make([]byte, 140)
+
+	var count int
+	for nonce := uint32(0); nonce < 8 && count == 0; nonce++ {
+		binary.LittleEndian.PutUint32(header[108:], nonce)
+		sols, err := client.Solve(header)
+		if err != nil {
+			t.Fatalf("failed on nonce %d: %v", nonce, err)
+		}
+
+		for _, soln := range sols {
+			valid, err := client.Verify(header, soln)
+			if err != nil {
+				t.Errorf("failed on nonce %d: %v", nonce, err)
+			} else if !valid {
+				t.Errorf("failed on nonce %d: invalid solution", nonce)
+			}
+		}
+
+		count += len(sols)
+	}
+
+	if count == 0 {
+		t.Errorf("no solutions found")
+	}
+}
+
+func TestSolveLimits(t *testing.T) {
+	header := make([]byte, 140)
+
+	client := New(96, 5, "ZcashPoW", false)
+	client.SetMemoryBudget(1 << 16)
+	if _, err := client.Solve(header); !errors.Is(err, powerr.ErrInvalidInput) {
+		t.Errorf("expected memory budget error, have %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client.SetMemoryBudget(0)
+	if _, err := client.SolveContext(ctx, header); err != context.Canceled {
+		t.Errorf("expected cancellation error, have %v", err)
+	}
+}
+
+// cancelAfterContext is cancelled once Err has been checked a number of times.
+type cancelAfterContext struct {
+	context.Context
+	checks int
+}
+
+func (ctx *cancelAfterContext) Err() error {
+	if ctx.checks--; ctx.checks < 0 {
+		return context.Canceled
+	}
+
+	return nil
+}
+
+func TestCollideCancel(t *testing.T) {
+	// groups of 5 rows, so that the start of a group is never a multiple of
+	// the check interval within the round
+	const count = 5 * 1000
+
+	prev := &solverRound{rowLen: 4}
+	for i := 0; i < count; i++ {
+		prev.hashes = append(prev.hashes, byte(i/5>>8), byte(i/5), byte(i), 0)
+	}
+
+	s := &solver{n: 96, k: 5}
+	ctx := &cancelAfterContext{Context: context.Background(), checks: 1}
+	if _, err := s.collide(ctx, prev, 2, 2, false); err != context.Canceled {
+		t.Errorf("expected cancellation error, have %v", err)
+	}
+}
+
+func TestCompactSize(t *testing.T) {
+	tests := []struct {
+		size    uint64
+		encoded []byte
+	}{
+		{
+			size:    0,
+			encoded: []byte{0x00},
+		},
+		{
+			size:    0xfc,
+			encoded: []byte{0xfc},
+		},
+		{
+			size:    0xfd,
+			encoded: []byte{0xfd, 0xfd, 0x00},
+		},
+		{
+			size:    1344,
+			encoded: []byte{0xfd, 0x40, 0x05},
+		},
+		{
+			size:    0x10000,
+			encoded: []byte{0xfe, 0x00, 0x00, 0x01, 0x00},
+		},
+		{
+			size:    0x100000000,
+			encoded: []byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00},
+		},
+	}
+
+	for i, tt := range tests {
+		encoded := AppendCompactSize(nil, tt.size)
+		if !bytes.Equal(encoded, tt.encoded) {
+			t.Errorf("failed on %d: encoding mismatch: have %x, want %x", i, encoded, tt.encoded)
+		}
+
+		size, width, err := ReadCompactSize(tt.encoded)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if size != tt.size || width != len(tt.encoded) {
+			t.Errorf("failed on %d: decoding mismatch: have %d (%d bytes), want %d", i, size, width, tt.size)
+		}
+	}
+
+	invalid := [][]byte{
+		{},
+		{0xfd, 0x40},
+		{0xfd, 0xfc, 0x00},
+		{0xfe, 0xff, 0xff, 0x00, 0x00},
+	}
+
+	for i, encoded := range invalid {
+		if _, _, err := ReadCompactSize(encoded); err == nil {
+			t.Errorf("failed on invalid %d: expected error", i)
+		}
+	}
+}
+
+func TestVerifyIndices(t *testing.T) {
+	client := New(96, 5, "ZcashPoW", false)
+	header := bytes.Join([][]byte{
+		[]byte("Equihash is an asymmetric PoW based on the Generalised Birthday problem."),
+		[]byte{
+			1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		},
+	}, nil)
+	indices := []uint32{
+		2261, 15185, 36112, 104243, 23779, 118390, 118332, 130041,
+		32642, 69878, 76925, 80080, 45858, 116805, 92842, 111026,
+		15972, 115059, 85191, 90330, 68190, 122819, 81830, 91132,
+		23460, 49807, 52426, 80391, 69567, 114474, 104973, 122568,
+	}
+
+	valid, err := client.VerifyIndices(header, indices)
+	if err != nil {
+		t.Errorf("failed to verify indices: %v", err)
+	} else if !valid {
+		t.Errorf("invalid indices")
+	}
+
+	soln, err := IndicesToMinimal(96, 5, indices)
+	if err != nil {
+		t.Fatalf("failed to encode indices: %v", err)
+	} else if len(soln) != client.SolutionSize() {
+		t.Errorf("solution length mismatch: have %d, want %d", len(soln), client.SolutionSize())
+	}
+
+	parsed, err := client.ParseSolution(SolutionToCompactSize(soln))
+	if err != nil {
+		t.Errorf("failed to parse prefixed solution: %v", err)
+	} else if !bytes.Equal(parsed, soln) {
+		t.Errorf("prefixed solution mismatch: have %x, want %x", parsed, soln)
+	}
+
+	parsed, err = client.ParseSolution(soln)
+	if err != nil {
+		t.Errorf("failed to parse minimal solution: %v", err)
+	} else if !bytes.Equal(parsed, soln) {
+		t.Errorf("minimal solution mismatch: have %x, want %x", parsed, soln)
+	}
+
+	if _, err := client.ParseSolution(soln[1:]); err == nil {
+		t.Errorf("expected error for truncated solution")
+	}
+
+	indices[0] = 1 << 17
+	if _, err := client.VerifyIndices(header, indices); err == nil {
+		t.Errorf("expected error for out of range index")
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	client := New(96, 5, "ZcashPoW", false)
+	client.SetWorkers(2)
+
+	header := make([]byte, 140)
+	sols, err := client.Solve(header)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(sols) == 0 {
+		t.Fatalf("no solution found")
+	}
+
+	var jobs []VerifyJob
+	for _, soln := range sols {
+		corrupted := append([]byte{}, soln...)
+		corrupted[len(corrupted)/2] ^= 0xff
+
+		jobs = append(jobs,
+			VerifyJob{Header: header, Soln: soln},
+			VerifyJob{Header: header, Soln: corrupted},
+			VerifyJob{Header: header, Soln: soln[1:]},
+		)
+	}
+	jobs = append(jobs, VerifyJob{Header: header, Soln: make([]byte, 68)})
+
+	results, err := client.VerifyBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, job := range jobs {
+		valid, err := client.Verify(job.Header, job.Soln)
+		if results[i].Valid != valid {
+			t.Errorf("failed on %d: valid mismatch: have %t, want %t", i, results[i].Valid, valid)
+		} else if (err != nil) != (results[i].Err != nil) {
+			t.Errorf("failed on %d: error mismatch: have %v, want %v", i, results[i].Err, err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package equihash
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// ctxCheckInterval is the number of rows processed between checks
+// of the context for cancellation.
+const ctxCheckInterval = 1 << 12
+
+// solverRound holds the rows of a round of Wagner's algorithm: the part of
+// the xored hash that is still to collide and the pair of rows from the
+// previous round it was built from (the index for the initial round).
+type solverRound struct {
+	rowLen uint32
+	hashes []byte
+	refs   []uint32
+}
+
+func (r *solverRound) len() int {
+	return len(r.hashes) / int(r.rowLen)
+}
+
+func (r *solverRound) hash(i int) []byte {
+	return r.hashes[i*int(r.rowLen) : (i+1)*int(r.rowLen)]
+}
+
+type solver struct {
+	n, k          uint32
+	personal      []byte
+	personalState []byte
+	twist         bool
+	budget        uint64
+	used          uint64
+}
+
+// reserve accounts for size more bytes of memory, failing if the budget
+// would be exceeded.
+func (s *solver) reserve(size uint64) error {
+	s.used += size
+	if s.budget > 0 && s.used > s.budget {
+		return &powerr.BudgetError{Budget: s.budget}
+	}
+
+	return nil
+}
+
+func (s *solver) release(size uint64) {
+	s.used -= size
+}
+
+// generate computes the expanded hash of every initial index on all cores.
+func (s *solver) generate(ctx context.Context, header []byte) (*solverRound, error) {
+	cBitLen := collisionBitLength(s.n, s.k)
+	numIndices := uint32(1) << (cBitLen + 1)
+	perHash := indicesPerHashOutput(s.n)
+	nBytes := (s.n + 7) / 8
+
+	round := &solverRound{rowLen: hashLength(s.n, s.k)}
+	if err := s.reserve(uint64(numIndices) * uint64(round.rowLen+4)); err != nil {
+		return nil, err
+	}
+
+	round.hashes = make([]byte, int(numIndices)*int(round.rowLen))
+	round.refs = make([]uint32, numIndices)
+
+	numHashes := int((numIndices + perHash - 1) / perHash)
+	threads := runtime.NumCPU()
+	batch := (numHashes + threads - 1) / threads
+
+	var pend sync.WaitGroup
+	errs := make([]error, threads)
+	pend.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func(id int) {
+			defer pend.Done()
+
+			first := id * batch
+			limit := first + batch
+			if limit > numHashes {
+				limit = numHashes
+			}
+
+			for g := first; g < limit; g++ {
+				if g%ctxCheckInterval == 0 && ctx.Err() != nil {
+					errs[id] = ctx.Err()
+					return
+				}
+
+				hash := generateHash(header, s.personalState, uint32(g), hashOutput(s.n), s.twist)
+				for j := uint32(0); j < perHash; j++ {
+					index := uint32(g)*perHash + j
+					if index >= numIndices {
+						break
+					}
+
+					expanded, err := expandArray(hash[j*nBytes:(j+1)*nBytes], cBitLen, 0)
+					if err != nil {
+						errs[id] = err
+						return
+					}
+
+					copy(round.hash(int(index)), expanded)
+					round.refs[index] = index
+				}
+			}
+		}(i)
+	}
+
+	pend.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return round, nil
+}
+
+// collide sorts the rows of the previous round by their first keyLen bytes
+// and combines every pair of rows within a group of equal keys, dropping the
+// first trim bytes of the xored hash. Pairs xoring to zero before the final
+// round are dropped, since they almost always come from duplicate indices.
+func (s *solver) collide(ctx context.Context, prev *solverRound, keyLen, trim uint32, final bool) (*solverRound, error) {
+	count := prev.len()
+	if err := s.reserve(uint64(count) * 4); err != nil {
+		return nil, err
+	}
+	defer s.release(uint64(count) * 4)
+
+	order := make([]uint32, count)
+	for i := range order {
+		order[i] = uint32(i)
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		return bytes.Compare(prev.hash(int(order[i]))[:keyLen], prev.hash(int(order[j]))[:keyLen]) < 0
+	})
+
+	next := &solverRound{rowLen: prev.rowLen - trim}
+	if next.rowLen == 0 {
+		next.rowLen = 1
+	}
+
+	// start advances by the size of each group, so the rows processed since
+	// the last check are counted instead, starting with a check
+	unchecked := ctxCheckInterval
+	for start := 0; start < count; {
+		if unchecked >= ctxCheckInterval {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			unchecked = 0
+		}
+
+		key := prev.hash(int(order[start]))[:keyLen]
+		end := start + 1
+		for end < count && bytes.Equal(prev.hash(int(order[end]))[:keyLen], key) {
+			end++
+		}
+
+		for a := start; a < end; a++ {
+			for b := a + 1; b < end; b++ {
+				hashA, hashB := prev.hash(int(order[a])), prev.hash(int(order[b]))
+				row := make([]byte, next.rowLen)
+				var nonZero bool
+				for i := trim; i < prev.rowLen; i++ {
+					row[i-trim] = hashA[i] ^ hashB[i]
+					nonZero = nonZero || row[i-trim] != 0
+				}
+
+				if nonZero && final {
+					continue
+				} else if !nonZero && !final {
+					continue
+				}
+
+				if err := s.reserve(uint64(next.rowLen) + 8); err != nil {
+					return nil, err
+				}
+
+				next.hashes = append(next.hashes, row...)
+				next.refs = append(next.refs, order[a], order[b])
+			}
+		}
+
+		unchecked += end - start
+		start = end
+	}
+
+	return next, nil
+}
+
+// indices walks back the rounds to collect the indices of a row, with
+// the subtree starting with the smallest index first at each level.
+func indices(rounds []*solverRound, round, row int) []uint32 {
+	if round == 0 {
+		return []uint32{rounds[0].refs[row]}
+	}
+
+	left := indices(rounds, round-1, int(rounds[round].refs[2*row]))
+	right := indices(rounds, round-1, int(rounds[round].refs[2*row+1]))
+	if right[0] < left[0] {
+		left, right = right, left
+	}
+
+	return append(left, right...)
+}
+
+func distinctIndices(indices []uint32) bool {
+	seen := make(map[uint32]bool, len(indices))
+	for _, index := range indices {
+		if seen[index] {
+			return false
+		}
+		seen[index] = true
+	}
+
+	return true
+}
+
+// solve runs Wagner's algorithm over the header and returns every valid
+// solution found, minimal encoded.
+func (s *solver) solve(ctx context.Context, header []byte) ([][]byte, error) {
+	cByteLen := collisionByteLength(s.n, s.k)
+
+	initial, err := s.generate(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	rounds := []*solverRound{initial}
+	for r := uint32(1); r <= s.k; r++ {
+		prev := rounds[len(rounds)-1]
+		final := r == s.k
+
+		// the final round needs the two remaining collision chunks to be equal
+		keyLen := cByteLen
+		if final {
+			keyLen = prev.rowLen
+		}
+
+		next, err := s.collide(ctx, prev, keyLen, cByteLen, final)
+		if err != nil {
+			return nil, err
+		}
+
+		// only the references of the previous rounds are still needed
+		s.release(uint64(len(prev.hashes)))
+		prev.hashes = nil
+
+		rounds = append(rounds, next)
+	}
+
+	var sols [][]byte
+	seen := make(map[string]bool)
+	last := rounds[len(rounds)-1]
+	for row := 0; row < len(last.refs)/2; row++ {
+		solIndices := indices(rounds, len(rounds)-1, row)
+		if !distinctIndices(solIndices) {
+			continue
+		}
+
+		soln, err := indicesToMinimal(s.n, s.k, solIndices)
+		if err != nil {
+			return nil, err
+		} else if seen[string(soln)] {
+			continue
+		}
+		seen[string(soln)] = true
+
+		if valid, _ := verify(s.n, s.k, s.personal, header, soln, s.twist); valid {
+			sols = append(sols, soln)
+		}
+	}
+
+	return sols, nil
+}
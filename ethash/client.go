@@ -3,28 +3,61 @@
 package ethash
 
 import (
-	"fmt"
-	"runtime"
+	"context"
+	"math/big"
 
+	"github.com/sencha-dev/powkit/internal/batch"
 	"github.com/sencha-dev/powkit/internal/common"
+	"github.com/sencha-dev/powkit/internal/crypto"
 	"github.com/sencha-dev/powkit/internal/dag"
+	"github.com/sencha-dev/powkit/internal/search"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
+// SearchResult is the nonce found by Search, along with its mix and digest.
+type SearchResult = search.Result
+
+// ComputeJob is a single nonce to compute with ComputeBatch.
+type ComputeJob = batch.ComputeJob
+
+// ComputeResult is the outcome of a ComputeJob.
+type ComputeResult = batch.ComputeResult
+
+// StoredCache is a cache, L1 cache or dataset file in the storage directory.
+type StoredCache = dag.StoredFile
+
+// CacheEvent reports a step in the lifecycle of a cache, see SetEventHandler.
+type CacheEvent = dag.Event
+
+// Storage keeps the caches and datasets, see SetStorage.
+type Storage = dag.Storage
+
 type Client struct {
-	data *dag.DAG
+	data    *dag.DAG
+	hash512 func([]byte) []byte
+	hash256 func([]byte) []byte
+	workers int
 }
 
 func New(cfg dag.Config) *Client {
+	return newClient(cfg, crypto.Keccak512, crypto.Keccak256)
+}
+
+func newClient(cfg dag.Config, hash512, hash256 func([]byte) []byte) *Client {
 	client := &Client{
-		data: dag.New(cfg),
+		data:    dag.New(cfg),
+		hash512: hash512,
+		hash256: hash256,
 	}
 
 	return client
 }
 
-func NewEthereum() *Client {
-	var cfg = dag.Config{
-		Name:       "ETH",
+// newConfig returns the configuration shared by the chains that kept
+// Ethereum's original Ethash parameters.
+func newConfig(name string) dag.Config {
+	return dag.Config{
+		Name:       name,
 		Revision:   23,
 		StorageDir: common.DefaultDir(".powcache"),
 
@@ -45,43 +78,168 @@ func NewEthereum() *Client {
 		CachesCount:    3,
 		CachesLockMmap: false,
 
+		FullDataset:      false,
+		DatasetsCount:    1,
+		DatasetsLockMmap: false,
+
 		L1Enabled: false,
 	}
+}
 
-	return New(cfg)
+func NewEthereum() *Client {
+	return New(newConfig("ETH"))
 }
 
 func NewEthereumClassic() *Client {
-	var cfg = dag.Config{
-		Name:       "ETC",
-		Revision:   23,
-		StorageDir: common.DefaultDir(".powcache"),
+	cfg := newConfig("ETC")
+	cfg.EpochSchedule = []dag.EpochSegment{{Height: 11700000, Length: 60000}} // ECIP-1099
 
-		DatasetInitBytes:   1 << 30,
-		DatasetGrowthBytes: 1 << 23,
-		CacheInitBytes:     1 << 24,
-		CacheGrowthBytes:   1 << 17,
+	return New(cfg)
+}
 
-		CacheSizes:   dag.NewLookupTable(cacheSizes, 2048),
-		DatasetSizes: dag.NewLookupTable(datasetSizes, 2048),
+func NewEthereumPoW() *Client {
+	return New(newConfig("ETHW"))
+}
 
-		DatasetParents:  256,
-		EpochLength:     60000,
-		SeedEpochLength: 30000,
+func NewCallisto() *Client {
+	return New(newConfig("CLO"))
+}
 
-		CacheRounds:    3,
-		CachesCount:    3,
-		CachesLockMmap: false,
+func NewExpanse() *Client {
+	return New(newConfig("EXP"))
+}
 
-		L1Enabled: false,
+// NewUbiq returns a Ubqhash client, which generates the cache with blake2b-512
+// instead of keccak-512 starting at the UIP1 epoch.
+func NewUbiq() *Client {
+	const uip1Epoch = 22
+
+	cfg := newConfig("UBQ")
+	cfg.CacheHasher = func(epoch uint64) crypto.Hasher {
+		if epoch >= uip1Epoch {
+			return crypto.NewBlake2b512Hasher()
+		}
+
+		return crypto.NewKeccak512Hasher()
 	}
 
 	return New(cfg)
 }
 
+// NewHypra returns an EthashB3 client, which replaces every keccak hash of
+// Ethash with blake3 (the 512 bit hashes using the extendable output) and
+// uses 32000 block epochs.
+func NewHypra() *Client {
+	cfg := newConfig("HYP")
+	cfg.EpochLength = 32000
+	cfg.SeedEpochLength = 32000
+	cfg.SeedHasher = crypto.NewBlake3256Hasher
+	cfg.CacheHasher = func(uint64) crypto.Hasher { return crypto.NewBlake3512Hasher() }
+	cfg.DatasetHasher = crypto.NewBlake3512Hasher
+
+	return newClient(cfg, crypto.Blake3512, crypto.Blake3256)
+}
+
+// Epoch returns the Ethash epoch of the height.
+func (c *Client) Epoch(height uint64) uint64 {
+	return c.data.CalcEpoch(height)
+}
+
+// ChainEpoch returns the chain's epoch number, which differs from Epoch on ETC.
+func (c *Client) ChainEpoch(height uint64) uint64 {
+	return c.data.ChainEpoch(height)
+}
+
+// SeedHash returns the seed of the Ethash cache of the epoch.
+func (c *Client) SeedHash(epoch uint64) []byte {
+	return c.data.EpochSeed(epoch)
+}
+
+// CacheSize returns the size in bytes of the Ethash cache of the epoch.
+func (c *Client) CacheSize(epoch uint64) uint64 {
+	return c.data.CacheSize(epoch)
+}
+
+// DatasetSize returns the size in bytes of the Ethash dataset of the epoch.
+func (c *Client) DatasetSize(epoch uint64) uint64 {
+	return c.data.DatasetSize(epoch)
+}
+
+// CachedEpochs returns the epochs whose Ethash cache is held in memory.
+func (c *Client) CachedEpochs() []uint64 {
+	return c.data.CachedEpochs()
+}
+
+// SetEventHandler sets the function called on cache lifecycle events, such as
+// a stored file failing validation and being regenerated. It may be called
+// while the client is in use.
+func (c *Client) SetEventHandler(handler func(CacheEvent)) {
+	c.data.SetEventHandler(handler)
+}
+
+// StoredCaches returns the files of the chain in the storage directory. The
+// epochs of the files of older revisions are not resolved.
+func (c *Client) StoredCaches() ([]StoredCache, error) {
+	return c.data.StoredFiles()
+}
+
+// VerifyStoredCache compares the stored cache of the epoch with a freshly
+// generated copy, returning an error matching powerr.ErrCorruptedCache if
+// they differ.
+func (c *Client) VerifyStoredCache(ctx context.Context, epoch uint64) error {
+	return c.data.VerifyStoredCache(ctx, epoch)
+}
+
+// GenerateCaches stores the caches of the epochs from first to last inclusive
+// ahead of use, without keeping them in memory.
+func (c *Client) GenerateCaches(ctx context.Context, first, last uint64) error {
+	return c.data.GenerateStoredCaches(ctx, first, last)
+}
+
+// SetStorage sets where the caches and datasets are kept, memory mapped files
+// in ~/.powcache by default. It must be called before the client is used.
+func (c *Client) SetStorage(storage Storage) {
+	c.data.SetStorage(storage)
+}
+
+// SetCacheBudget limits the total memory of the caches held to roughly the
+// given number of bytes, evicting the least recently used ones beyond it.
+// Zero means only the number of caches is limited.
+func (c *Client) SetCacheBudget(bytes uint64) {
+	c.data.SetCachesMaxBytes(bytes)
+}
+
+// SetCacheLookAhead sets the number of epochs following the newest one used
+// whose caches are generated in the background, one by default. Zero disables
+// the look-ahead.
+func (c *Client) SetCacheLookAhead(epochs int) {
+	c.data.SetCachesLookAhead(epochs)
+}
+
+// PinEpoch generates the cache of the epoch and keeps it in memory until
+// UnpinEpoch, regardless of the cache budget.
+func (c *Client) PinEpoch(ctx context.Context, epoch uint64) error {
+	return c.data.PinCache(ctx, epoch)
+}
+
+// UnpinEpoch lets the cache of the epoch be evicted again.
+func (c *Client) UnpinEpoch(epoch uint64) {
+	c.data.UnpinCache(epoch)
+}
+
+// Close releases the Ethash caches and datasets, which are loaded again if used.
+func (c *Client) Close() {
+	c.data.Close()
+}
+
+// SetFullDataset toggles computing Ethash hashes from the full dataset.
+func (c *Client) SetFullDataset(enabled bool) {
+	c.data.SetFullDataset(enabled)
+}
+
 func (c *Client) Compute(hash []byte, height, nonce uint64) ([]byte, []byte, error) {
 	if len(hash) != 32 {
-		return nil, nil, fmt.Errorf("hash must be 32 bytes")
+		return nil, nil, &powerr.LengthError{Field: "hash", Want: 32, Have: len(hash)}
 	}
 
 	epoch := c.data.CalcEpoch(height)
@@ -89,8 +247,89 @@ func (c *Client) Compute(hash []byte, height, nonce uint64) ([]byte, []byte, err
 	cache := c.data.GetCache(epoch)
 	lookup := c.data.NewLookupFunc512(cache, epoch)
 
-	mix, digest := hashimoto(hash, nonce, size, lookup)
-	runtime.KeepAlive(cache)
+	mix, digest := hashimoto(hash, nonce, size, lookup, c.hash512, c.hash256)
+	cache.Release()
 
 	return mix, digest, nil
 }
+
+// SetWorkers sets the number of goroutines Search and ComputeBatch run on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
+// Search computes count nonces starting at startNonce and returns the lowest
+// one whose digest is less than or equal to target, or nil if none of them is.
+func (c *Client) Search(ctx context.Context, hash []byte, height, startNonce, count uint64, target *big.Int) (*SearchResult, error) {
+	if len(hash) != 32 {
+		return nil, &powerr.LengthError{Field: "hash", Want: 32, Have: len(hash)}
+	}
+
+	epoch := c.data.CalcEpoch(height)
+	size := c.data.DatasetSize(epoch)
+	cache, err := c.data.GetCacheContext(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	newCompute := func() search.ComputeFunc {
+		lookup := c.data.NewLookupFunc512(cache, epoch)
+
+		return func(nonce uint64) ([]byte, []byte, error) {
+			mix, digest := hashimoto(hash, nonce, size, lookup, c.hash512, c.hash256)
+
+			return mix, digest, nil
+		}
+	}
+
+	result, err := search.Search(ctx, startNonce, count, c.workers, target, newCompute)
+	cache.Release()
+
+	return result, err
+}
+
+// ComputeBatch computes every job, grouping them by epoch so that the cache
+// of each epoch is fetched once and every worker reuses its lookup. The
+// results are in the order of the jobs, the error is only returned if ctx
+// is done before all of them are computed.
+func (c *Client) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	results := make([]ComputeResult, len(jobs))
+	groups := batch.GroupBy(len(jobs), func(i int) uint64 {
+		return c.data.CalcEpoch(jobs[i].Height)
+	})
+
+	for _, group := range groups {
+		indices := group.Indices
+		epoch := group.Key
+		size := c.data.DatasetSize(epoch)
+		cache, err := c.data.GetCacheContext(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+
+		newWorker := func() func(int) {
+			lookup := c.data.NewLookupFunc512(cache, epoch)
+
+			return func(i int) {
+				index := indices[i]
+				job := jobs[index]
+				if len(job.Hash) != 32 {
+					results[index].Err = &powerr.LengthError{Field: "hash", Want: 32, Have: len(job.Hash)}
+					return
+				}
+
+				mix, digest := hashimoto(job.Hash, job.Nonce, size, lookup, c.hash512, c.hash256)
+				results[index] = ComputeResult{Mix: mix, Digest: digest}
+			}
+		}
+
+		err = batch.Run(ctx, len(indices), c.workers, newWorker)
+		cache.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
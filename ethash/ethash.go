@@ -32,8 +32,9 @@ const (
 )
 
 // hashimoto aggregates data from the full dataset in order to produce our final
-// value for a particular header hash and nonce.
-func hashimoto(hash []byte, nonce, datasetSize uint64, lookup func(index uint32) []uint32) ([]byte, []byte) {
+// value for a particular header hash and nonce. The seed is hashed with hash512
+// and the final digest with hash256 (keccak for Ethash).
+func hashimoto(hash []byte, nonce, datasetSize uint64, lookup func(index uint32) []uint32, hash512, hash256 func([]byte) []byte) ([]byte, []byte) {
 	// Calculate the number of theoretical rows (we use one buffer nonetheless)
 	rows := uint32(datasetSize / mixBytes)
 
@@ -42,7 +43,7 @@ func hashimoto(hash []byte, nonce, datasetSize uint64, lookup func(index uint32)
 	copy(seed, hash)
 	binary.LittleEndian.PutUint64(seed[32:], nonce)
 
-	seed = crypto.Keccak512(seed)
+	seed = hash512(seed)
 	seedHead := binary.LittleEndian.Uint32(seed)
 
 	// Start the mix with replicated seed
@@ -72,5 +73,5 @@ func hashimoto(hash []byte, nonce, datasetSize uint64, lookup func(index uint32)
 		binary.LittleEndian.PutUint32(digest[i*4:], val)
 	}
 
-	return digest, crypto.Keccak256(append(seed, digest...))
+	return digest, hash256(append(seed, digest...))
 }
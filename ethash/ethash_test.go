@@ -2,6 +2,7 @@ package ethash
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/sencha-dev/powkit/internal/common/testutil"
@@ -94,3 +95,148 @@ func TestComputeEthereumClassic(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeVariants(t *testing.T) {
+	hash := testutil.MustDecodeHex("0x69e71ffd37268b6cf7096cdd917c2c175eaaee8eb7afed4b5cf8521b09024818")
+	ethereum := NewEthereum()
+
+	tests := []struct {
+		client *Client
+		height uint64
+		same   bool
+	}{
+		{
+			client: NewEthereumPoW(),
+			height: 0,
+			same:   true,
+		},
+		{
+			client: NewCallisto(),
+			height: 0,
+			same:   true,
+		},
+		{
+			client: NewExpanse(),
+			height: 0,
+			same:   true,
+		},
+		{
+			client: NewUbiq(),
+			height: 1,
+			same:   true,
+		},
+		{
+			client: NewUbiq(),
+			height: 22*30000 + 1,
+			same:   false,
+		},
+		{
+			client: NewHypra(),
+			height: 0,
+			same:   false,
+		},
+	}
+
+	for i, tt := range tests {
+		mix, digest, err := tt.client.Compute(hash, tt.height, 0)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		}
+
+		ethMix, ethDigest, err := ethereum.Compute(hash, tt.height, 0)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		}
+
+		same := bytes.Equal(mix, ethMix) && bytes.Equal(digest, ethDigest)
+		if same != tt.same {
+			t.Errorf("failed on %d: match with ethereum mismatch: have %t, want %t", i, same, tt.same)
+		}
+	}
+}
+
+func TestEpochs(t *testing.T) {
+	tests := []struct {
+		client *Client
+		height uint64
+		epoch  uint64
+	}{
+		{NewUbiq(), 22*30000 - 1, 21},
+		{NewUbiq(), 22 * 30000, 22}, // UIP-1
+		{NewHypra(), 31999, 0},
+		{NewHypra(), 32000, 1},
+	}
+
+	for i, tt := range tests {
+		if epoch := tt.client.Epoch(tt.height); epoch != tt.epoch {
+			t.Errorf("failed on %d: epoch mismatch: have %d, want %d", i, epoch, tt.epoch)
+		}
+	}
+}
+
+// @TODO: add mainnet blocks on both sides of the epoch length change to
+// TestComputeEthereumClassic, until then the switch is checked against the
+// Ethereum seeds and sizes it is specified with.
+func TestEthereumClassicSchedule(t *testing.T) {
+	tests := []struct {
+		height    uint64
+		epoch     uint64
+		seedEpoch uint64 // Ethereum epoch of the same seed
+		sizeEpoch uint64 // Ethereum epoch of the same sizes
+	}{
+		{height: 11699999, epoch: 389, seedEpoch: 389, sizeEpoch: 389},
+		{height: 11700000, epoch: 390, seedEpoch: 390, sizeEpoch: 195},
+		{height: 11759999, epoch: 390, seedEpoch: 390, sizeEpoch: 195},
+		{height: 11760000, epoch: 391, seedEpoch: 392, sizeEpoch: 196},
+	}
+
+	classic := NewEthereumClassic()
+	ethereum := NewEthereum()
+	for i, tt := range tests {
+		epoch := classic.Epoch(tt.height)
+		if epoch != tt.epoch {
+			t.Errorf("failed on %d: epoch mismatch: have %d, want %d", i, epoch, tt.epoch)
+			continue
+		}
+
+		if seed, want := classic.SeedHash(epoch), ethereum.SeedHash(tt.seedEpoch); bytes.Compare(seed, want) != 0 {
+			t.Errorf("failed on %d: seed mismatch: have %x, want %x", i, seed, want)
+		}
+		if size, want := classic.CacheSize(epoch), ethereum.CacheSize(tt.sizeEpoch); size != want {
+			t.Errorf("failed on %d: cache size mismatch: have %d, want %d", i, size, want)
+		}
+		if size, want := classic.DatasetSize(epoch), ethereum.DatasetSize(tt.sizeEpoch); size != want {
+			t.Errorf("failed on %d: dataset size mismatch: have %d, want %d", i, size, want)
+		}
+	}
+}
+
+func TestComputeBatch(t *testing.T) {
+	hash := testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff")
+	client := NewEthereum()
+	client.SetWorkers(3)
+
+	var jobs []ComputeJob
+	for nonce := uint64(0); nonce < 40; nonce++ {
+		jobs = append(jobs, ComputeJob{Hash: hash, Height: (nonce % 2) * 30000, Nonce: nonce})
+	}
+	jobs = append(jobs, ComputeJob{Hash: hash[:31], Height: 0, Nonce: 0})
+
+	results, err := client.ComputeBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, job := range jobs {
+		mix, digest, err := client.Compute(job.Hash, job.Height, job.Nonce)
+		if (err != nil) != (results[i].Err != nil) {
+			t.Errorf("failed on %d: error mismatch: have %v, want %v", i, results[i].Err, err)
+		} else if bytes.Compare(results[i].Mix, mix) != 0 {
+			t.Errorf("failed on %d: mix mismatch: have %x, want %x", i, results[i].Mix, mix)
+		} else if bytes.Compare(results[i].Digest, digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, results[i].Digest, digest)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package firopow
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/sencha-dev/powkit/internal/common/testutil"
@@ -414,3 +415,31 @@ func TestComputeFiro(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeBatch(t *testing.T) {
+	hash := testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff")
+	client := NewFiro()
+	client.SetWorkers(3)
+
+	var jobs []ComputeJob
+	for nonce := uint64(0); nonce < 40; nonce++ {
+		jobs = append(jobs, ComputeJob{Hash: hash, Height: (nonce % 2) * 1300, Nonce: nonce})
+	}
+	jobs = append(jobs, ComputeJob{Hash: hash[:31], Height: 0, Nonce: 0})
+
+	results, err := client.ComputeBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, job := range jobs {
+		mix, digest, err := client.Compute(job.Hash, job.Height, job.Nonce)
+		if (err != nil) != (results[i].Err != nil) {
+			t.Errorf("failed on %d: error mismatch: have %v, want %v", i, results[i].Err, err)
+		} else if bytes.Compare(results[i].Mix, mix) != 0 {
+			t.Errorf("failed on %d: mix mismatch: have %x, want %x", i, results[i].Mix, mix)
+		} else if bytes.Compare(results[i].Digest, digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, results[i].Digest, digest)
+		}
+	}
+}
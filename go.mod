@@ -6,6 +6,5 @@ require (
 	github.com/dchest/blake2b v1.0.0
 	github.com/edsrzf/mmap-go v1.0.0
 	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa
+	golang.org/x/sys v0.7.0
 )
-
-require golang.org/x/sys v0.0.0-20220722155257-8c9f86f7a55f // indirect
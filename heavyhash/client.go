@@ -1,10 +1,31 @@
 package heavyhash
 
 import (
-	"fmt"
+	"context"
+	"math/big"
+
+	"github.com/sencha-dev/powkit/internal/batch"
+	"github.com/sencha-dev/powkit/internal/search"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
-type Client struct{}
+// SearchResult is the nonce found by Search, along with its digest (the mix
+// is always nil).
+type SearchResult = search.Result
+
+// ComputeJob is a single nonce to compute with ComputeBatch.
+type ComputeJob struct {
+	Hash      []byte
+	Timestamp int64
+	Nonce     uint64
+}
+
+// ComputeResult is the outcome of a ComputeJob.
+type ComputeResult = batch.ComputeResult
+
+type Client struct {
+	workers int
+}
 
 func New() *Client {
 	client := &Client{}
@@ -18,10 +39,56 @@ func NewKaspa() *Client {
 
 func (c *Client) Compute(hash []byte, timestamp int64, nonce uint64) ([]byte, error) {
 	if len(hash) != 32 {
-		return nil, fmt.Errorf("hash must be 32 bytes")
+		return nil, &powerr.LengthError{Field: "hash", Want: 32, Have: len(hash)}
 	}
 
 	digest := heavyHash(hash, timestamp, nonce)
 
 	return digest, nil
 }
+
+// SetWorkers sets the number of goroutines Search and ComputeBatch run on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
+// Search computes count nonces starting at startNonce and returns the lowest
+// one whose digest is less than or equal to target, or nil if none of them is.
+func (c *Client) Search(ctx context.Context, hash []byte, timestamp int64, startNonce, count uint64, target *big.Int) (*SearchResult, error) {
+	if len(hash) != 32 {
+		return nil, &powerr.LengthError{Field: "hash", Want: 32, Have: len(hash)}
+	}
+
+	newCompute := func() search.ComputeFunc {
+		return func(nonce uint64) ([]byte, []byte, error) {
+			return nil, heavyHash(hash, timestamp, nonce), nil
+		}
+	}
+
+	return search.Search(ctx, startNonce, count, c.workers, target, newCompute)
+}
+
+// ComputeBatch computes every job across the workers. The results are in the
+// order of the jobs, the error is only returned if ctx is done before all of
+// them are computed.
+func (c *Client) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	results := make([]ComputeResult, len(jobs))
+	newWorker := func() func(int) {
+		return func(i int) {
+			job := jobs[i]
+			if len(job.Hash) != 32 {
+				results[i].Err = &powerr.LengthError{Field: "hash", Want: 32, Have: len(job.Hash)}
+				return
+			}
+
+			results[i] = ComputeResult{Digest: heavyHash(job.Hash, job.Timestamp, job.Nonce)}
+		}
+	}
+
+	if err := batch.Run(ctx, len(jobs), c.workers, newWorker); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,139 @@
+// Package batch implements the bounded worker pool behind the ComputeBatch
+// and VerifyBatch methods of every client.
+package batch
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// chunkSize is the number of consecutive jobs a worker claims at once,
+// which is also how often it checks for cancellation.
+const chunkSize = 1 << 4
+
+// ComputeJob is a single nonce to compute for a header hash.
+type ComputeJob struct {
+	Hash   []byte
+	Height uint64
+	Nonce  uint64
+}
+
+// ComputeResult is the outcome of a ComputeJob. The mix is nil for the
+// algorithms that do not produce one.
+type ComputeResult struct {
+	Mix    []byte
+	Digest []byte
+	Err    error
+}
+
+// VerifyJob is a single solution to verify for a header.
+type VerifyJob struct {
+	Header []byte
+	Soln   []byte
+}
+
+// VerifyResult is the outcome of a VerifyJob.
+type VerifyResult struct {
+	Valid bool
+	Err   error
+}
+
+// Group is a set of jobs sharing the same key, such as an epoch.
+type Group struct {
+	Key     uint64
+	Indices []int
+}
+
+// GroupBy groups the indices of n jobs by key, ordered by key and keeping
+// the order of the jobs within each group.
+func GroupBy(n int, key func(i int) uint64) []Group {
+	positions := make(map[uint64]int)
+	var groups []Group
+	for i := 0; i < n; i++ {
+		k := key(i)
+		pos, ok := positions[k]
+		if !ok {
+			pos = len(groups)
+			positions[k] = pos
+			groups = append(groups, Group{Key: k})
+		}
+		groups[pos].Indices = append(groups[pos].Indices, i)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Key < groups[j].Key
+	})
+
+	return groups
+}
+
+// Run processes n jobs across workers goroutines (runtime.NumCPU() if workers
+// is not positive, never more than n). newWorker is called once per goroutine
+// so that each one can keep its own hashers and lookups, and the function it
+// returns is called with the index of every job the goroutine claims. Run
+// stops claiming jobs once ctx is done and returns its error, unless every
+// job was processed anyway.
+func Run(ctx context.Context, n, workers int, newWorker func() func(i int)) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		mu        sync.Mutex
+		next      int
+		completed int
+	)
+
+	claim := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		start := next
+		next += chunkSize
+		if next > n {
+			next = n
+		}
+
+		return start, next
+	}
+
+	complete := func(count int) {
+		mu.Lock()
+		completed += count
+		mu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			process := newWorker()
+			for ctx.Err() == nil {
+				start, end := claim()
+				if start == end {
+					return
+				}
+
+				for i := start; i < end; i++ {
+					process(i)
+				}
+				complete(end - start)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if completed == n {
+		return nil
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,90 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	keys := []uint64{3, 1, 3, 2, 1, 3}
+	groups := GroupBy(len(keys), func(i int) uint64 {
+		return keys[i]
+	})
+
+	expected := []Group{
+		{Key: 1, Indices: []int{1, 4}},
+		{Key: 2, Indices: []int{3}},
+		{Key: 3, Indices: []int{0, 2, 5}},
+	}
+
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("groups mismatch: have %v, want %v", groups, expected)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		n       int
+		workers int
+	}{
+		{n: 0, workers: 4},
+		{n: 1, workers: 0},
+		{n: 1000, workers: 3},
+		{n: 1000, workers: 64},
+	}
+
+	for i, tt := range tests {
+		var workers int32
+		processed := make([]int32, tt.n)
+		newWorker := func() func(int) {
+			atomic.AddInt32(&workers, 1)
+
+			return func(i int) {
+				atomic.AddInt32(&processed[i], 1)
+			}
+		}
+
+		if err := Run(context.Background(), tt.n, tt.workers, newWorker); err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		}
+
+		if tt.workers > 0 && int(workers) > tt.workers {
+			t.Errorf("failed on %d: too many workers: have %d, want at most %d", i, workers, tt.workers)
+		}
+
+		for j, count := range processed {
+			if count != 1 {
+				t.Errorf("failed on %d: job %d processed %d times", i, j, count)
+				break
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Run(ctx, 1000, 2, func() func(int) { return func(int) {} })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, have %v", err)
+	}
+
+	// cancelling while the last job is processed does not discard the
+	// complete results
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	err = Run(ctx, chunkSize, 1, func() func(int) {
+		return func(i int) {
+			if i == chunkSize-1 {
+				cancel()
+			}
+		}
+	})
+	if err != nil {
+		t.Errorf("expected no error once every job is processed, have %v", err)
+	}
+}
@@ -26,3 +26,15 @@ func Blake2b256(data []byte) []byte {
 	out := blake2b.Sum256(data)
 	return out[:]
 }
+
+func Blake2b512(data []byte) []byte {
+	out := blake2b.Sum512(data)
+	return out[:]
+}
+
+func NewBlake2b512Hasher() Hasher {
+	return func(dest []byte, data []byte) {
+		out := blake2b.Sum512(data)
+		copy(dest, out[:])
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 Jack O'Connor and Samuel Neves
+
+package crypto
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	blake3BlockLen = 64
+	blake3ChunkLen = 1024
+
+	blake3ChunkStart = 1 << 0
+	blake3ChunkEnd   = 1 << 1
+	blake3Parent     = 1 << 2
+	blake3Root       = 1 << 3
+)
+
+var blake3IV = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+var blake3MsgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+func blake3G(state *[16]uint32, a, b, c, d int, mx, my uint32) {
+	state[a] = state[a] + state[b] + mx
+	state[d] = bits.RotateLeft32(state[d]^state[a], -16)
+	state[c] = state[c] + state[d]
+	state[b] = bits.RotateLeft32(state[b]^state[c], -12)
+	state[a] = state[a] + state[b] + my
+	state[d] = bits.RotateLeft32(state[d]^state[a], -8)
+	state[c] = state[c] + state[d]
+	state[b] = bits.RotateLeft32(state[b]^state[c], -7)
+}
+
+func blake3Round(state *[16]uint32, m *[16]uint32) {
+	// mix the columns
+	blake3G(state, 0, 4, 8, 12, m[0], m[1])
+	blake3G(state, 1, 5, 9, 13, m[2], m[3])
+	blake3G(state, 2, 6, 10, 14, m[4], m[5])
+	blake3G(state, 3, 7, 11, 15, m[6], m[7])
+
+	// mix the diagonals
+	blake3G(state, 0, 5, 10, 15, m[8], m[9])
+	blake3G(state, 1, 6, 11, 12, m[10], m[11])
+	blake3G(state, 2, 7, 8, 13, m[12], m[13])
+	blake3G(state, 3, 4, 9, 14, m[14], m[15])
+}
+
+func blake3Compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	state := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		blake3IV[0], blake3IV[1], blake3IV[2], blake3IV[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+
+	m := *block
+	for i := 0; i < 7; i++ {
+		blake3Round(&state, &m)
+		if i < 6 {
+			var permuted [16]uint32
+			for j := range permuted {
+				permuted[j] = m[blake3MsgPermutation[j]]
+			}
+			m = permuted
+		}
+	}
+
+	for i := 0; i < 8; i++ {
+		state[i] ^= state[i+8]
+		state[i+8] ^= cv[i]
+	}
+
+	return state
+}
+
+func blake3Words(block []byte) [16]uint32 {
+	var padded [blake3BlockLen]byte
+	copy(padded[:], block)
+
+	var words [16]uint32
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(padded[i*4:])
+	}
+
+	return words
+}
+
+// blake3Output holds the inputs of a compression, which is either chained
+// into a parent node or, for the root node, extended into any output length.
+type blake3Output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o *blake3Output) chainingValue() [8]uint32 {
+	state := blake3Compress(&o.cv, &o.block, o.counter, o.blockLen, o.flags)
+
+	var cv [8]uint32
+	copy(cv[:], state[:8])
+
+	return cv
+}
+
+func (o *blake3Output) rootBytes(dest []byte) {
+	for counter := uint64(0); len(dest) > 0; counter++ {
+		state := blake3Compress(&o.cv, &o.block, counter, o.blockLen, o.flags|blake3Root)
+
+		var block [blake3BlockLen]byte
+		for i, word := range state {
+			binary.LittleEndian.PutUint32(block[i*4:], word)
+		}
+
+		dest = dest[copy(dest, block[:]):]
+	}
+}
+
+// blake3ChunkOutput compresses all but the last block of a chunk of at most
+// 1024 bytes and returns the output of its last block.
+func blake3ChunkOutput(key [8]uint32, chunk []byte, counter uint64) *blake3Output {
+	cv := key
+	flags := uint32(blake3ChunkStart)
+	for len(chunk) > blake3BlockLen {
+		block := blake3Words(chunk[:blake3BlockLen])
+		state := blake3Compress(&cv, &block, counter, blake3BlockLen, flags)
+		copy(cv[:], state[:8])
+
+		chunk = chunk[blake3BlockLen:]
+		flags = 0
+	}
+
+	return &blake3Output{
+		cv:       cv,
+		block:    blake3Words(chunk),
+		counter:  counter,
+		blockLen: uint32(len(chunk)),
+		flags:    flags | blake3ChunkEnd,
+	}
+}
+
+func blake3ParentOutput(key, left, right [8]uint32) *blake3Output {
+	var block [16]uint32
+	copy(block[:8], left[:])
+	copy(block[8:], right[:])
+
+	return &blake3Output{
+		cv:       key,
+		block:    block,
+		counter:  0,
+		blockLen: blake3BlockLen,
+		flags:    blake3Parent,
+	}
+}
+
+// Blake3 returns the size byte BLAKE3 hash of data (sizes over 32 bytes use
+// the extendable output).
+func Blake3(data []byte, size int) []byte {
+	key := blake3IV
+
+	// every completed subtree is merged as soon as its sibling is complete,
+	// which leaves one chaining value per set bit of the chunk count
+	var stack [][8]uint32
+	var chunks uint64
+	for len(data) > blake3ChunkLen {
+		cv := blake3ChunkOutput(key, data[:blake3ChunkLen], chunks).chainingValue()
+		data = data[blake3ChunkLen:]
+		chunks++
+
+		for total := chunks; total&1 == 0; total >>= 1 {
+			cv = blake3ParentOutput(key, stack[len(stack)-1], cv).chainingValue()
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, cv)
+	}
+
+	output := blake3ChunkOutput(key, data, chunks)
+	for i := len(stack) - 1; i >= 0; i-- {
+		output = blake3ParentOutput(key, stack[i], output.chainingValue())
+	}
+
+	dest := make([]byte, size)
+	output.rootBytes(dest)
+
+	return dest
+}
+
+func Blake3256(data []byte) []byte {
+	return Blake3(data, 32)
+}
+
+func Blake3512(data []byte) []byte {
+	return Blake3(data, 64)
+}
+
+func NewBlake3256Hasher() Hasher {
+	return func(dest []byte, data []byte) {
+		copy(dest, Blake3(data, 32))
+	}
+}
+
+func NewBlake3512Hasher() Hasher {
+	return func(dest []byte, data []byte) {
+		copy(dest, Blake3(data, 64))
+	}
+}
@@ -0,0 +1,59 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sencha-dev/powkit/internal/common/testutil"
+)
+
+func TestBlake3(t *testing.T) {
+	tests := []struct {
+		inputLen int
+		size     int
+		hash     []byte
+	}{
+		{
+			inputLen: 0,
+			size:     32,
+			hash:     testutil.MustDecodeHex("af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"),
+		},
+		{
+			inputLen: 0,
+			size:     131,
+			hash: testutil.MustDecodeHex("af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262" +
+				"e00f03e7b69af26b7faaf09fcd333050338ddfe085b8cc869ca98b206c08243a" +
+				"26f5487789e8f660afe6c99ef9e0c52b92e7393024a80459cf91f476f9ffdbda" +
+				"7001c22e159b402631f277ca96f2defdf1078282314e763699a31c5363165421" +
+				"cce14d"),
+		},
+		{
+			inputLen: 1,
+			size:     32,
+			hash:     testutil.MustDecodeHex("2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"),
+		},
+		{
+			inputLen: 1024,
+			size:     32,
+			hash:     testutil.MustDecodeHex("42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"),
+		},
+		{
+			inputLen: 1025,
+			size:     32,
+			hash:     testutil.MustDecodeHex("d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"),
+		},
+	}
+
+	for i, tt := range tests {
+		// the official test vectors use the repeating sequence 0, 1, ..., 250
+		input := make([]byte, tt.inputLen)
+		for j := range input {
+			input[j] = byte(j % 251)
+		}
+
+		hash := Blake3(input, tt.size)
+		if !bytes.Equal(hash, tt.hash) {
+			t.Errorf("failed on %d: have %x, want %x", i, hash, tt.hash)
+		}
+	}
+}
@@ -1,18 +1,26 @@
 package crypto
 
 type SipHasher struct {
-	v0 uint64
-	v1 uint64
-	v2 uint64
-	v3 uint64
+	v0   uint64
+	v1   uint64
+	v2   uint64
+	v3   uint64
+	rotE uint64
 }
 
 func NewSipHasher(v0, v1, v2, v3 uint64) *SipHasher {
+	return NewSipHasherRot(v0, v1, v2, v3, 21)
+}
+
+// NewSipHasherRot creates a hasher with a non-standard rotation constant
+// for v3 (Cuckarood uses 25 instead of 21 as an anti-ASIC tweak).
+func NewSipHasherRot(v0, v1, v2, v3, rotE uint64) *SipHasher {
 	hasher := &SipHasher{
-		v0: v0,
-		v1: v1,
-		v2: v2,
-		v3: v3,
+		v0:   v0,
+		v1:   v1,
+		v2:   v2,
+		v3:   v3,
+		rotE: rotE,
 	}
 
 	return hasher
@@ -33,7 +41,7 @@ func (h *SipHasher) SipRound() {
 	h.v3 ^= h.v2
 
 	h.v0 += h.v3
-	h.v3 = h.v3<<21 | h.v3>>43
+	h.v3 = h.v3<<h.rotE | h.v3>>(64-h.rotE)
 	h.v3 ^= h.v0
 
 	h.v2 += h.v1
@@ -94,25 +102,64 @@ func SipNode24Legacy(edgeMask uint64, siphashKeys [4]uint64, edge, uorv uint64)
 
 type SipBlockFunc func([4]uint64, uint64) uint64
 
-func SipBlock48(siphashKeys [4]uint64, edge uint64) uint64 {
-	const edgeBlockBits uint64 = 6
-	const edgeBlockSize uint64 = (1 << edgeBlockBits)
-	const edgeBlockMask uint64 = (edgeBlockSize - 1)
+const (
+	edgeBlockBits uint64 = 6
+	edgeBlockSize uint64 = (1 << edgeBlockBits)
+	edgeBlockMask uint64 = (edgeBlockSize - 1)
+)
 
-	hasher := NewSipHasher(siphashKeys[0], siphashKeys[1], siphashKeys[2], siphashKeys[3])
+// sipBlock hashes the whole block of edges containing edge with a single
+// hasher. Unless xorAll is set, the hash of the edge is xored with the last
+// hash of the block, otherwise with all the following hashes of the block.
+func sipBlock(hasher *SipHasher, hash func(*SipHasher, uint64), edge uint64, xorAll bool) uint64 {
 	block := make([]uint64, edgeBlockSize)
 	edge0 := edge & ^edgeBlockMask
 
 	var i uint64
 	for i = 0; i < edgeBlockSize; i++ {
-		hasher.Hash48(edge0 + uint64(i))
+		hash(hasher, edge0+i)
 		block[i] = hasher.XorLanes()
 	}
 
-	last := block[edgeBlockMask]
-	for i = 0; i < edgeBlockMask; i++ {
-		block[i] ^= last
+	index := edge & edgeBlockMask
+	value := block[index]
+
+	from := edgeBlockMask
+	if xorAll || index == edgeBlockMask {
+		from = index + 1
+	}
+
+	for i = from; i < edgeBlockSize; i++ {
+		value ^= block[i]
 	}
 
-	return block[edge&edgeBlockMask]
+	return value
+}
+
+// SipBlock24 is the block hasher of Grin's Cuckaroo29.
+func SipBlock24(siphashKeys [4]uint64, edge uint64) uint64 {
+	hasher := NewSipHasher(siphashKeys[0], siphashKeys[1], siphashKeys[2], siphashKeys[3])
+
+	return sipBlock(hasher, (*SipHasher).Hash24, edge, false)
+}
+
+// SipBlock24Rot25 is the block hasher of Cuckarood, using a rotation of 25.
+func SipBlock24Rot25(siphashKeys [4]uint64, edge uint64) uint64 {
+	hasher := NewSipHasherRot(siphashKeys[0], siphashKeys[1], siphashKeys[2], siphashKeys[3], 25)
+
+	return sipBlock(hasher, (*SipHasher).Hash24, edge, false)
+}
+
+// SipBlock24XorAll is the block hasher of Cuckaroom and Cuckarooz, where the
+// hash of the edge is xored with all the following hashes of the block.
+func SipBlock24XorAll(siphashKeys [4]uint64, edge uint64) uint64 {
+	hasher := NewSipHasher(siphashKeys[0], siphashKeys[1], siphashKeys[2], siphashKeys[3])
+
+	return sipBlock(hasher, (*SipHasher).Hash24, edge, true)
+}
+
+func SipBlock48(siphashKeys [4]uint64, edge uint64) uint64 {
+	hasher := NewSipHasher(siphashKeys[0], siphashKeys[1], siphashKeys[2], siphashKeys[3])
+
+	return sipBlock(hasher, (*SipHasher).Hash48, edge, false)
 }
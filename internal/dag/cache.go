@@ -17,112 +17,146 @@
 package dag
 
 import (
-	"os"
-	"runtime"
+	"context"
 	"sync"
 	"time"
 )
 
 type cache struct {
-	epoch uint64
-	once  sync.Once
-	used  time.Time
-	cache dataFile
-	l1    dataFile
+	epoch     uint64
+	size      uint64 // Size of the cache and L1 cache in bytes
+	used      time.Time
+	refs      int           // References held by the DAG and the callers, protected by the DAG lock
+	keepOlder bool          // Whether to keep the files of older epochs once generated
+	mu        sync.Mutex    // Protects the generation state below
+	done      bool          // Whether the cache content was generated
+	pending   chan struct{} // Closed once the in-flight generation returns
+	cache     Buffer
+	l1        Buffer
 }
 
 func (c *cache) Cache() []uint32 {
-	return c.cache.data
+	if c.cache == nil {
+		return nil
+	}
+
+	return c.cache.Data()
 }
 
 func (c *cache) L1() []uint32 {
-	return c.l1.data
+	if c.l1 == nil {
+		return nil
+	}
+
+	return c.l1.Data()
 }
 
-// generate ensures that the cache content is generated before use.
-func (c *cache) generate(cfg *DAG) {
-	c.once.Do(func() {
-		size := cfg.CacheSize(c.epoch)
-		seed := cfg.SeedHash(c.epoch*cfg.EpochLength + 1)
+// generate ensures that the cache content is generated before use. If the
+// context is cancelled, generation is aborted and will be retried on the
+// next call. Concurrent callers wait for the in-flight generation.
+func (c *cache) generate(ctx context.Context, cfg *DAG) error {
+	for {
+		c.mu.Lock()
+		if c.done {
+			c.mu.Unlock()
+			return nil
+		}
+
+		if c.pending == nil {
+			pending := make(chan struct{})
+			c.pending = pending
+			c.mu.Unlock()
 
-		// If we don't store anything on disk, generate and return.
-		if cfg.StorageDir == "" {
-			c.cache.data = make([]uint32, size/4)
-			cfg.generateCache(c.cache.data, c.epoch, seed)
+			err := c.doGenerate(ctx, cfg)
 
-			if cfg.L1Enabled {
-				c.l1.data = make([]uint32, cfg.L1CacheNumItems)
-				cfg.generateL1Cache(c.l1.data, c.cache.data)
-			}
+			c.mu.Lock()
+			c.done = err == nil
+			c.pending = nil
+			close(pending)
+			c.mu.Unlock()
+
+			return err
+		}
+
+		pending := c.pending
+		c.mu.Unlock()
 
-			return
+		// wait for the in-flight generation, retrying if it failed
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+	}
+}
 
-		cachePath := cfg.cacheStorageLocation(seed[:8])
-		l1Path := cfg.l1StorageLocation(seed[:8])
+func (c *cache) doGenerate(ctx context.Context, cfg *DAG) error {
+	size := cfg.CacheSize(c.epoch)
+	seed := cfg.EpochSeed(c.epoch)
+	storage := cfg.storage()
 
-		// We're about to mmap the file, ensure that the mapping is cleaned up when the
-		// cache becomes unused.
-		runtime.SetFinalizer(c, (*cache).finalizer)
+	progress := func(percent float64) {
+		cfg.emit(Event{Type: EventCacheProgress, Epoch: c.epoch, Percent: percent})
+	}
 
-		// Try to load the file from disk and memory map it
-		var err error
-		c.cache, err = memoryMap(cachePath, cfg.CachesLockMmap)
-		needsCache := err != nil
+	// On failure release any memory, generation is retried on the next call
+	fail := func(err error) error {
+		c.unmap()
+		return err
+	}
 
-		needsL1 := cfg.L1Enabled
-		if cfg.L1Enabled {
-			c.l1, err = memoryMap(l1Path, cfg.CachesLockMmap)
-			needsL1 = err != nil
+	// Try to load the cache from the storage, corrupted files are regenerated
+	// like missing ones
+	var err error
+	cacheEntry := cfg.entry(FileCache, c.epoch, seed, size, cfg.CachesLockMmap)
+	if c.cache, err = cfg.load(storage, cacheEntry); err != nil {
+		generator := func(buffer []uint32) error {
+			return cfg.generateCacheContext(ctx, buffer, c.epoch, seed, progress)
 		}
 
-		if !needsL1 && !needsCache {
-			return
+		if c.cache, err = cfg.store(ctx, storage, cacheEntry, generator); err != nil {
+			return fail(err)
 		}
+	}
 
-		// No usable previous cache available, create a new cache file to fill
-		if needsCache {
-			cacheGenerator := func(buffer []uint32) { cfg.generateCache(buffer, c.epoch, seed) }
-			c.cache, err = memoryMapAndGenerate(cachePath, size, cfg.CachesLockMmap, cacheGenerator)
-			if err != nil {
-				c.cache.data = make([]uint32, size/4)
-				cfg.generateCache(c.cache.data, c.epoch, seed)
+	if cfg.L1Enabled {
+		l1Entry := cfg.entry(FileL1, c.epoch, seed, cfg.L1CacheSize, cfg.CachesLockMmap)
+		if c.l1, err = cfg.load(storage, l1Entry); err != nil {
+			generator := func(buffer []uint32) error {
+				cfg.generateL1Cache(buffer, c.cache.Data())
+				return nil
 			}
-		}
 
-		if needsL1 {
-			l1Generator := func(buffer []uint32) { cfg.generateL1Cache(buffer, c.cache.data) }
-			c.l1, err = memoryMapAndGenerate(l1Path, cfg.L1CacheSize, cfg.CachesLockMmap, l1Generator)
-			if err != nil {
-				c.l1.data = make([]uint32, cfg.L1CacheNumItems)
-				cfg.generateL1Cache(c.l1.data, c.cache.data)
+			if c.l1, err = cfg.store(ctx, storage, l1Entry, generator); err != nil {
+				return fail(err)
 			}
 		}
+	}
 
-		// Iterate over all previous instances and delete old ones
-		for ep := int(c.epoch) - cfg.CachesCount; ep >= 0; ep-- {
-			seed := cfg.SeedHash(uint64(ep)*cfg.EpochLength + 1)
+	if _, ok := storage.(storageDir); c.keepOlder || !ok {
+		return nil
+	}
 
-			cachePath := cfg.cacheStorageLocation(seed[:8])
-			os.Remove(cachePath)
+	// Iterate over all previous instances and delete old ones, unless other
+	// processes still have them mapped
+	for ep := int(c.epoch) - cfg.cachesCount(); ep >= 0; ep-- {
+		seed := cfg.EpochSeed(uint64(ep))
+		storage.Remove(cfg.entry(FileCache, uint64(ep), seed, 0, false))
+		storage.Remove(cfg.entry(FileL1, uint64(ep), seed, 0, false))
+	}
 
-			l1Path := cfg.l1StorageLocation(seed[:8])
-			os.Remove(l1Path)
-		}
-	})
+	return nil
 }
 
-// finalizer unmaps the memory and closes the file.
-func (c *cache) finalizer() {
-	if c.cache.mmap != nil {
-		c.cache.mmap.Unmap()
-		c.cache.dump.Close()
-		c.cache.mmap, c.cache.dump = nil, nil
+// unmap releases the memory of the cache once the last reference is released.
+func (c *cache) unmap() {
+	if c.cache != nil {
+		c.cache.Release()
+		c.cache = nil
 	}
 
-	if c.l1.mmap != nil {
-		c.l1.mmap.Unmap()
-		c.l1.dump.Close()
-		c.l1.mmap, c.l1.dump = nil, nil
+	if c.l1 != nil {
+		c.l1.Release()
+		c.l1 = nil
 	}
 }
@@ -16,6 +16,10 @@
 
 package dag
 
+import (
+	"github.com/sencha-dev/powkit/internal/crypto"
+)
+
 const (
 	hashBytes = 64 // Hash length in bytes
 	hashWords = 16 // Number of 32 bit ints in a hash
@@ -35,10 +39,20 @@ func NewLookupTable(table []uint64, maxEpoch uint64) *LookupTable {
 	return lookupTable
 }
 
+// EpochSegment changes the epoch length starting at a height, which must be a
+// multiple of the previous epoch length (Ethereum Classic doubles its epoch
+// length at block 11700000).
+type EpochSegment struct {
+	Height uint64
+	Length uint64
+}
+
 type Config struct {
 	Name       string
 	Revision   int
 	StorageDir string
+	Storage    Storage     // Optional storage of the caches and datasets, files in StorageDir if nil
+	OnEvent    func(Event) // Optional callback for cache lifecycle events
 
 	// size variables
 	DatasetInitBytes   uint64 // Bytes in dataset at genesis
@@ -54,12 +68,25 @@ type Config struct {
 	MixBytes        uint64
 	DatasetParents  uint32
 	EpochLength     uint64
-	SeedEpochLength uint64 // ETC uses a different seed epoch length
+	SeedEpochLength uint64         // ETC uses a different seed epoch length
+	EpochSchedule   []EpochSegment // Optional epoch length changes, ordered by height
+
+	// hash functions, keccak256 and keccak512 if nil
+	SeedHasher    func() crypto.Hasher             // 256 bit hash chained to derive the seed of each epoch
+	CacheHasher   func(epoch uint64) crypto.Hasher // 512 bit hash filling the cache of an epoch
+	DatasetHasher func() crypto.Hasher             // 512 bit hash of dataset items
 
 	// cache variables
-	CacheRounds    int
-	CachesCount    int // Maximum number of caches to keep before eviction (only init, don't modify)
-	CachesLockMmap bool
+	CacheRounds     int
+	CachesCount     int    // Maximum number of caches to keep before eviction, unlimited if zero and CachesMaxBytes is set (only init, don't modify)
+	CachesMaxBytes  uint64 // Maximum total size of the caches and L1 caches to keep before eviction, unlimited if zero
+	CachesLookAhead int    // Number of future epochs to pre-generate caches for, 1 if zero and none if negative
+	CachesLockMmap  bool
+
+	// dataset variables
+	FullDataset      bool // Generate the full dataset instead of computing items from the cache
+	DatasetsCount    int  // Maximum number of full datasets to keep before eviction (only init, don't modify)
+	DatasetsLockMmap bool
 
 	// L1 variables
 	L1Enabled       bool
@@ -17,53 +17,300 @@
 package dag
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"math/big"
 	"path/filepath"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sencha-dev/powkit/internal/crypto"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
 type DAG struct {
 	Config
-	mu     sync.Mutex        // Protects the per-epoch map of verification caches
-	caches map[uint64]*cache // Currently maintained verification caches
-	future *cache            // Pre-generated cache for the estimated future DAG
+	mu       sync.Mutex          // Protects the per-epoch maps, the reference counts and the limits
+	caches   map[uint64]*cache   // Currently maintained verification caches
+	futures  map[uint64]*cache   // Pre-generated caches for the estimated future epochs
+	pinned   map[uint64]bool     // Epochs whose caches are never evicted
+	newest   uint64              // Newest epoch requested, the future caches follow it
+	datasets map[uint64]*dataset // Currently maintained full datasets
+	onEvent  atomic.Value        // Event handler set with SetEventHandler, replacing OnEvent
+
+	ctx    context.Context    // Context for background generation, cancelled and replaced on Close
+	cancel context.CancelFunc // Cancels the background generation context
+	wg     *sync.WaitGroup    // Tracks the background generation using ctx
 }
 
 func New(cfg Config) *DAG {
 	dag := &DAG{
-		Config: cfg,
-		caches: make(map[uint64]*cache),
+		Config:   cfg,
+		caches:   make(map[uint64]*cache),
+		futures:  make(map[uint64]*cache),
+		datasets: make(map[uint64]*dataset),
 	}
 
 	return dag
 }
 
+// Close aborts any background generation of future caches, waiting for it to
+// return, and drops the caches, datasets and pins held by the DAG. Their memory
+// is released as soon as the references still in use are released, the DAG
+// generating or loading them again if used afterwards.
+func (dag *DAG) Close() {
+	dag.mu.Lock()
+
+	// the generation started after this call gets a new context and wait
+	// group, so that it is neither cancelled nor waited for by this call
+	wg := dag.wg
+	if dag.cancel != nil {
+		dag.cancel()
+	}
+	dag.ctx, dag.cancel, dag.wg = nil, nil, nil
+
+	for epoch, c := range dag.caches {
+		delete(dag.caches, epoch)
+		dag.unrefCache(c)
+	}
+
+	for epoch, c := range dag.futures {
+		delete(dag.futures, epoch)
+		dag.unrefCache(c)
+	}
+
+	for epoch, d := range dag.datasets {
+		delete(dag.datasets, epoch)
+		dag.unrefDataset(d)
+	}
+
+	dag.pinned = nil
+	dag.mu.Unlock()
+
+	if wg != nil {
+		wg.Wait()
+	}
+}
+
+// background returns the context and the wait group of background generation,
+// creating them on first use after New or Close. The lock must be held.
+func (dag *DAG) background() (context.Context, *sync.WaitGroup) {
+	if dag.ctx == nil {
+		dag.ctx, dag.cancel = context.WithCancel(context.Background())
+		dag.wg = new(sync.WaitGroup)
+	}
+
+	return dag.ctx, dag.wg
+}
+
 /* helpers */
 
-func (d *DAG) cacheStorageLocation(seed []byte) string {
-	name := fmt.Sprintf("cache-%s-R%d-%x", d.Name, d.Revision, seed)
-	path := filepath.Join(d.StorageDir, name)
+// storage returns where the caches and datasets are kept: the configured
+// Storage, files in StorageDir or the heap if neither is set.
+func (d *DAG) storage() Storage {
+	if d.Storage != nil {
+		return d.Storage
+	} else if d.StorageDir != "" {
+		return NewFileStorage(d.StorageDir)
+	}
+
+	return NewHeapStorage()
+}
+
+// storageDir returns the directory of the storage, empty if it is not backed
+// by one.
+func (d *DAG) storageDir() string {
+	if dir, ok := d.storage().(storageDir); ok {
+		return dir.Dir()
+	}
+
+	return ""
+}
+
+func (d *DAG) entry(kind FileKind, epoch uint64, seed []byte, size uint64, lock bool) Entry {
+	entry := Entry{
+		Kind:       kind,
+		Name:       d.Name,
+		Revision:   d.Revision,
+		Epoch:      epoch,
+		Seed:       seed,
+		Size:       size,
+		LockMemory: lock,
+	}
+
+	return entry
+}
 
-	return path
+func (d *DAG) cacheStorageLocation(seed []byte) string {
+	return filepath.Join(d.storageDir(), d.entry(FileCache, 0, seed, 0, false).FileName())
 }
 
 func (d *DAG) l1StorageLocation(seed []byte) string {
-	name := fmt.Sprintf("l1-%s-R%d-%x", d.Name, d.Revision, seed)
-	path := filepath.Join(d.StorageDir, name)
+	return filepath.Join(d.storageDir(), d.entry(FileL1, 0, seed, 0, false).FileName())
+}
+
+func (d *DAG) datasetStorageLocation(seed []byte) string {
+	return filepath.Join(d.storageDir(), d.entry(FileDataset, 0, seed, 0, false).FileName())
+}
+
+// load loads an entry from the storage, reporting the files that fail
+// validation so that the caller regenerates them.
+func (d *DAG) load(storage Storage, entry Entry) (Buffer, error) {
+	buf, err := storage.Load(entry)
+	if errors.Is(err, powerr.ErrCorruptedCache) {
+		path := filepath.Join(d.storageDir(), entry.FileName())
+		d.emit(Event{Type: EventCacheCorrupted, Epoch: entry.Epoch, Path: path, Err: err})
+	} else if err == nil && entry.Kind == FileCache {
+		d.emit(Event{Type: EventCacheLoaded, Epoch: entry.Epoch, Path: buf.Path()})
+	}
+
+	return buf, err
+}
+
+// store generates an entry into the storage, falling back to the heap if the
+// storage fails, such as on a full disk. Events are only emitted for caches.
+func (d *DAG) store(ctx context.Context, storage Storage, entry Entry, generate func([]uint32) error) (Buffer, error) {
+	var generated bool
+	generator := func(data []uint32) error {
+		if !generated && entry.Kind == FileCache {
+			d.emit(Event{Type: EventCacheStarted, Epoch: entry.Epoch})
+		}
+		generated = true
+
+		return generate(data)
+	}
+
+	buf, err := storage.Store(ctx, entry, generator)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if buf, err = NewHeapStorage().Store(ctx, entry, generator); err != nil {
+			return nil, err
+		}
+	}
+
+	if entry.Kind == FileCache && buf.Path() != "" {
+		if generated {
+			d.emit(Event{Type: EventCacheWritten, Epoch: entry.Epoch, Path: buf.Path()})
+		} else {
+			d.emit(Event{Type: EventCacheLoaded, Epoch: entry.Epoch, Path: buf.Path()})
+		}
+	}
+
+	return buf, nil
+}
+
+// SetStorage sets where the caches and datasets are kept. It must be called
+// before the DAG is used.
+func (d *DAG) SetStorage(storage Storage) {
+	d.Storage = storage
+}
+
+func (d *DAG) cachesCount() int {
+	if d.CachesCount < 1 {
+		return 1
+	}
+
+	return d.CachesCount
+}
+
+func (d *DAG) datasetsCount() int {
+	if d.DatasetsCount < 1 {
+		return 1
+	}
 
-	return path
+	return d.DatasetsCount
+}
+
+/* hashers */
+
+func (d *DAG) seedHasher() crypto.Hasher {
+	if d.SeedHasher != nil {
+		return d.SeedHasher()
+	}
+
+	return crypto.NewKeccak256Hasher()
+}
+
+func (d *DAG) cacheHasher(epoch uint64) crypto.Hasher {
+	if d.CacheHasher != nil {
+		return d.CacheHasher(epoch)
+	}
+
+	return crypto.NewKeccak512Hasher()
+}
+
+func (d *DAG) datasetHasher() crypto.Hasher {
+	if d.DatasetHasher != nil {
+		return d.DatasetHasher()
+	}
+
+	return crypto.NewKeccak512Hasher()
 }
 
 /* calculations */
 
+// segment walks the epoch schedule while match accepts the first epoch and
+// height of the next segment, returning the first epoch, first height and
+// epoch length of the last accepted segment.
+func (d *DAG) segment(match func(epoch, height uint64) bool) (uint64, uint64, uint64) {
+	var epoch, height uint64
+	length := d.EpochLength
+	for _, next := range d.EpochSchedule {
+		nextEpoch := epoch + (next.Height-height)/length
+		if !match(nextEpoch, next.Height) {
+			break
+		}
+
+		epoch, height, length = nextEpoch, next.Height, next.Length
+	}
+
+	return epoch, height, length
+}
+
+// CalcEpoch returns the epoch of the height. Epochs are numbered sequentially
+// across the epoch schedule so that every epoch has its own seed, which means
+// that after an epoch length change they no longer match the chain's epoch
+// number (see sizeEpoch).
 func (d *DAG) CalcEpoch(height uint64) uint64 {
-	epoch := height / d.EpochLength
+	first, firstHeight, length := d.segment(func(_, next uint64) bool {
+		return height >= next
+	})
 
-	return epoch
+	return first + (height-firstHeight)/length
+}
+
+// ChainEpoch returns the chain's epoch number of the height, the height divided
+// by the epoch length in effect, which differs from CalcEpoch after an epoch
+// length change.
+func (d *DAG) ChainEpoch(height uint64) uint64 {
+	return d.sizeEpoch(d.CalcEpoch(height))
+}
+
+// EpochHeight returns the first height of the epoch.
+func (d *DAG) EpochHeight(epoch uint64) uint64 {
+	first, firstHeight, length := d.segment(func(next, _ uint64) bool {
+		return epoch >= next
+	})
+
+	return firstHeight + (epoch-first)*length
+}
+
+// sizeEpoch returns the chain's epoch number for the epoch (the height divided
+// by the epoch length in effect), which the cache and dataset sizes grow with.
+func (d *DAG) sizeEpoch(epoch uint64) uint64 {
+	first, firstHeight, length := d.segment(func(next, _ uint64) bool {
+		return epoch >= next
+	})
+
+	return (firstHeight + (epoch-first)*length) / length
+}
+
+// EpochSeed returns the seed hash of the epoch.
+func (d *DAG) EpochSeed(epoch uint64) []byte {
+	return d.SeedHash(d.EpochHeight(epoch) + 1)
 }
 
 func (d *DAG) SeedHash(height uint64) []byte {
@@ -72,15 +319,16 @@ func (d *DAG) SeedHash(height uint64) []byte {
 		return seed
 	}
 
-	keccak256Hasher := crypto.NewKeccak256Hasher()
+	seedHasher := d.seedHasher()
 	for i := 0; i < int(height/d.SeedEpochLength); i++ {
-		keccak256Hasher(seed, seed)
+		seedHasher(seed, seed)
 	}
 
 	return seed
 }
 
 func (d *DAG) CacheSize(epoch uint64) uint64 {
+	epoch = d.sizeEpoch(epoch)
 	if d.CacheSizes != nil && epoch < d.CacheSizes.maxEpoch {
 		return d.CacheSizes.table[epoch]
 	}
@@ -89,6 +337,7 @@ func (d *DAG) CacheSize(epoch uint64) uint64 {
 }
 
 func (d *DAG) DatasetSize(epoch uint64) uint64 {
+	epoch = d.sizeEpoch(epoch)
 	if d.DatasetSizes != nil && epoch < d.DatasetSizes.maxEpoch {
 		return d.DatasetSizes.table[epoch]
 	}
@@ -120,77 +369,449 @@ func (d *DAG) calcDatasetSize(epoch uint64) uint64 {
 
 /* cache */
 
-func (dag *DAG) GetCache(epoch uint64) *cache {
-	var c *cache
+// cacheRef is a reference to a verification cache returned by GetCache, which
+// keeps the cache and the full datasets read by its lookups mapped until it is
+// released.
+type cacheRef struct {
+	*cache
+	dag      *DAG
+	datasets []*dataset // Datasets referenced by the lookups, protected by the DAG lock
+	released bool
+}
+
+// Release drops the reference, unmapping the cache and the datasets once they
+// are evicted and no other reference is held. The cache and the lookups made
+// from it must not be used afterwards.
+func (r *cacheRef) Release() {
+	r.dag.mu.Lock()
+	defer r.dag.mu.Unlock()
+
+	if r.released {
+		return
+	}
+	r.released = true
+
+	r.dag.unrefCache(r.cache)
+	for _, d := range r.datasets {
+		r.dag.unrefDataset(d)
+	}
+	r.datasets = nil
+}
+
+// hold keeps a referenced dataset until the reference is released, dropping
+// the extra reference if it already holds the dataset.
+func (r *cacheRef) hold(d *dataset) *dataset {
+	r.dag.mu.Lock()
+	defer r.dag.mu.Unlock()
+
+	for _, held := range r.datasets {
+		if held == d {
+			r.dag.unrefDataset(d)
+			return d
+		}
+	}
+	r.datasets = append(r.datasets, d)
+
+	return d
+}
+
+// newCache returns a cache with the given number of references. The lock must
+// be held.
+func (dag *DAG) newCache(epoch uint64, refs int) *cache {
+	size := dag.CacheSize(epoch)
+	if dag.L1Enabled {
+		size += dag.L1CacheSize
+	}
+
+	return &cache{epoch: epoch, size: size, refs: refs}
+}
+
+// unrefCache drops a reference to the cache, unmapping it once none is left.
+// The lock must be held.
+func (dag *DAG) unrefCache(c *cache) {
+	c.refs--
+	if c.refs == 0 {
+		c.unmap()
+	}
+}
+
+// evictCaches removes the least recently used caches, except for keep and
+// the pinned epochs, until the caches held fit in CachesCount and
+// CachesMaxBytes. The evicted epochs are returned. The lock must be held.
+func (dag *DAG) evictCaches(keep *cache) []uint64 {
+	count := dag.CachesCount
+	if count < 1 && dag.CachesMaxBytes == 0 {
+		count = 1
+	}
+
+	var total uint64
+	for _, c := range dag.caches {
+		total += c.size
+	}
+
+	var evicted []uint64
+	for (count > 0 && len(dag.caches) > count) || (dag.CachesMaxBytes > 0 && total > dag.CachesMaxBytes) {
+		var evict *cache
+		for _, c := range dag.caches {
+			if c == keep || dag.pinned[c.epoch] {
+				continue
+			} else if evict == nil || evict.used.After(c.used) {
+				evict = c
+			}
+		}
+
+		if evict == nil {
+			break
+		}
+
+		delete(dag.caches, evict.epoch)
+		total -= evict.size
+		dag.unrefCache(evict)
+		evicted = append(evicted, evict.epoch)
+	}
+
+	return evicted
+}
+
+// lookAhead pre-generates the caches of the CachesLookAhead epochs following
+// the newest epoch requested so far, dropping the future caches outside of
+// them. The lock must be held.
+func (dag *DAG) lookAhead(epoch uint64) {
+	if epoch < dag.newest {
+		return
+	}
+	dag.newest = epoch
+
+	count := uint64(1)
+	if dag.CachesLookAhead < 0 {
+		count = 0
+	} else if dag.CachesLookAhead > 0 {
+		count = uint64(dag.CachesLookAhead)
+	}
+
+	for future, c := range dag.futures {
+		if future <= epoch || future > epoch+count {
+			delete(dag.futures, future)
+			dag.unrefCache(c)
+		}
+	}
+
+	var pending []*cache
+	for future := epoch + 1; future <= epoch+count && future > epoch; future++ {
+		if dag.caches[future] == nil && dag.futures[future] == nil {
+			// referenced by the DAG and the generating goroutine
+			c := dag.newCache(future, 2)
+			dag.futures[future] = c
+			pending = append(pending, c)
+		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	// the future caches are generated one at a time, nearest first
+	ctx, wg := dag.background()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for _, c := range pending {
+			c.generate(ctx, dag)
+
+			dag.mu.Lock()
+			dag.unrefCache(c)
+			dag.mu.Unlock()
+		}
+	}()
+}
+
+// GetCache returns the verification cache for the epoch, generating it if
+// needed. It blocks until the cache is ready. The cache must be released once
+// it is no longer used.
+func (dag *DAG) GetCache(epoch uint64) *cacheRef {
+	c, _ := dag.GetCacheContext(context.Background(), epoch)
+
+	return c
+}
+
+// GetCacheContext returns the verification cache for the epoch, generating
+// it if needed. If the context is cancelled before the cache is ready, the
+// context error is returned and generation is retried on the next call. The
+// cache must be released once it is no longer used.
+func (dag *DAG) GetCacheContext(ctx context.Context, epoch uint64) (*cacheRef, error) {
+	var evicted []uint64
 
 	dag.mu.Lock()
 	if dag.caches == nil {
 		dag.caches = make(map[uint64]*cache)
 	}
+	if dag.futures == nil {
+		dag.futures = make(map[uint64]*cache)
+	}
 
-	c = dag.caches[epoch]
+	c := dag.caches[epoch]
 	if c == nil {
-		// if cache limit is reached, evict the oldest cache entry
-		if len(dag.caches) >= dag.CachesCount {
-			var evict *cache
-			for _, cache := range dag.caches {
-				if evict == nil || evict.used.After(cache.used) {
-					evict = cache
-				}
-			}
-			delete(dag.caches, evict.epoch)
-		}
-
-		// use the pre generated dag if exists
-		if dag.future != nil && dag.future.epoch == epoch {
-			c, dag.future = dag.future, nil
+		// use the pre generated cache if exists, the DAG keeps its reference
+		if c = dag.futures[epoch]; c != nil {
+			delete(dag.futures, epoch)
 		} else {
-			c = &cache{epoch: epoch}
+			c = dag.newCache(epoch, 1)
 		}
 
 		dag.caches[epoch] = c
-		nextEpoch := epoch + 1
-		if dag.future == nil || dag.future.epoch <= epoch {
-			dag.future = &cache{epoch: nextEpoch}
-			go dag.future.generate(dag)
-		}
+		evicted = dag.evictCaches(c)
+		dag.lookAhead(epoch)
 	}
 
 	c.used = time.Now()
+	c.refs++
+	ref := &cacheRef{cache: c, dag: dag}
 	dag.mu.Unlock()
 
-	c.generate(dag)
+	// events are emitted without holding the lock
+	for _, epoch := range evicted {
+		dag.emit(Event{Type: EventCacheEvicted, Epoch: epoch})
+	}
 
-	return c
+	if err := c.generate(ctx, dag); err != nil {
+		ref.Release()
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// CachedEpochs returns the sorted epochs of the verification caches that are
+// generated and held in memory, not counting the pre-generated future caches.
+func (dag *DAG) CachedEpochs() []uint64 {
+	dag.mu.Lock()
+	caches := make([]*cache, 0, len(dag.caches))
+	for _, c := range dag.caches {
+		caches = append(caches, c)
+	}
+	dag.mu.Unlock()
+
+	epochs := make([]uint64, 0, len(caches))
+	for _, c := range caches {
+		c.mu.Lock()
+		if c.done {
+			epochs = append(epochs, c.epoch)
+		}
+		c.mu.Unlock()
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+
+	return epochs
+}
+
+// SetCachesMaxBytes sets the maximum total size of the caches held, evicting
+// the least recently used ones that exceed it. Zero removes the limit.
+func (dag *DAG) SetCachesMaxBytes(bytes uint64) {
+	dag.mu.Lock()
+	dag.CachesMaxBytes = bytes
+	evicted := dag.evictCaches(nil)
+	dag.mu.Unlock()
+
+	for _, epoch := range evicted {
+		dag.emit(Event{Type: EventCacheEvicted, Epoch: epoch})
+	}
+}
+
+// SetCachesLookAhead sets the number of future epochs whose caches are
+// pre-generated, taking effect on the next epoch requested. Zero disables it.
+func (dag *DAG) SetCachesLookAhead(epochs int) {
+	if epochs == 0 {
+		epochs = -1
+	}
+
+	dag.mu.Lock()
+	dag.CachesLookAhead = epochs
+	dag.mu.Unlock()
+}
+
+// PinCache generates the cache of the epoch and keeps it until UnpinCache,
+// exempting it from eviction. Pinned caches still count towards the limits of
+// the other caches.
+func (dag *DAG) PinCache(ctx context.Context, epoch uint64) error {
+	dag.mu.Lock()
+	if dag.pinned == nil {
+		dag.pinned = make(map[uint64]bool)
+	}
+	dag.pinned[epoch] = true
+	dag.mu.Unlock()
+
+	c, err := dag.GetCacheContext(ctx, epoch)
+	if err != nil {
+		dag.UnpinCache(epoch)
+		return err
+	}
+	c.Release()
+
+	return nil
+}
+
+// UnpinCache makes the cache of the epoch evictable again.
+func (dag *DAG) UnpinCache(epoch uint64) {
+	dag.mu.Lock()
+	delete(dag.pinned, epoch)
+	evicted := dag.evictCaches(nil)
+	dag.mu.Unlock()
+
+	for _, epoch := range evicted {
+		dag.emit(Event{Type: EventCacheEvicted, Epoch: epoch})
+	}
+}
+
+/* dataset */
+
+// SetFullDataset switches the lookup functions between computing dataset
+// items from the cache and reading them from the full dataset, which is
+// generated, and kept in the storage, once per epoch.
+func (dag *DAG) SetFullDataset(enabled bool) {
+	dag.mu.Lock()
+	dag.FullDataset = enabled
+	dag.mu.Unlock()
+}
+
+func (dag *DAG) fullDataset() bool {
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+
+	return dag.FullDataset
+}
+
+// unrefDataset drops a reference to the dataset, unmapping it once none is
+// left. The lock must be held.
+func (dag *DAG) unrefDataset(d *dataset) {
+	d.refs--
+	if d.refs == 0 {
+		d.unmap()
+	}
+}
+
+// GetDataset returns the full dataset for the epoch, generating it if needed.
+// The caller owns a reference to it, see unrefDataset. If generation fails,
+// or the DAG is closed meanwhile, the dataset is dropped so that the next call
+// retries and the error is returned.
+func (dag *DAG) GetDataset(epoch uint64) (*dataset, error) {
+	var d *dataset
+
+	dag.mu.Lock()
+	if dag.datasets == nil {
+		dag.datasets = make(map[uint64]*dataset)
+	}
+
+	d = dag.datasets[epoch]
+	if d == nil {
+		// if dataset limit is reached, evict the oldest dataset entry
+		if len(dag.datasets) >= dag.datasetsCount() {
+			var evict *dataset
+			for _, dataset := range dag.datasets {
+				if evict == nil || evict.used.After(dataset.used) {
+					evict = dataset
+				}
+			}
+			delete(dag.datasets, evict.epoch)
+			dag.unrefDataset(evict)
+		}
+
+		d = &dataset{epoch: epoch, refs: 1}
+		dag.datasets[epoch] = d
+	}
+
+	d.used = time.Now()
+	d.refs++
+	ctx, _ := dag.background()
+	dag.mu.Unlock()
+
+	if err := d.generate(ctx, dag); err != nil {
+		dag.mu.Lock()
+		if dag.datasets[epoch] == d {
+			delete(dag.datasets, epoch)
+			dag.unrefDataset(d)
+		}
+		dag.unrefDataset(d)
+		dag.mu.Unlock()
+
+		return nil, err
+	}
+
+	return d, nil
 }
 
 /* lookups */
 
 type LookupFunc func(index uint32) []uint32
 
-func (dag *DAG) NewLookupFunc512(c *cache, epoch uint64) LookupFunc {
-	keccak512Hasher := crypto.NewKeccak512Hasher()
+// NewLookupFunc512 returns a lookup for 512 bit dataset items. If the full
+// dataset is enabled the items are read from it, otherwise, or if the dataset
+// cannot be generated, they are computed from the cache on demand.
+func (dag *DAG) NewLookupFunc512(c *cacheRef, epoch uint64) LookupFunc {
+	if dag.fullDataset() {
+		if d, err := dag.GetDataset(epoch); err == nil {
+			return dag.NewDatasetLookupFunc512(c.hold(d))
+		}
+	}
+
+	datasetHasher := dag.datasetHasher()
+	lookup := func(index uint32) []uint32 {
+		return dag.generateDatasetItemUint(c.Cache(), index, 1, datasetHasher)
+	}
+
+	return lookup
+}
+
+func (dag *DAG) NewLookupFunc1024(c *cacheRef, epoch uint64) LookupFunc {
+	if dag.fullDataset() {
+		if d, err := dag.GetDataset(epoch); err == nil {
+			return dag.NewDatasetLookupFunc1024(c.hold(d))
+		}
+	}
+
+	datasetHasher := dag.datasetHasher()
 	lookup := func(index uint32) []uint32 {
-		return dag.generateDatasetItemUint(c.Cache(), index, 1, keccak512Hasher)
+		return dag.generateDatasetItemUint(c.Cache(), index, 2, datasetHasher)
 	}
 
 	return lookup
 }
 
-func (dag *DAG) NewLookupFunc1024(c *cache, epoch uint64) LookupFunc {
-	keccak512Hasher := crypto.NewKeccak512Hasher()
+func (dag *DAG) NewLookupFunc2048(c *cacheRef, epoch uint64) LookupFunc {
+	if dag.fullDataset() {
+		if d, err := dag.GetDataset(epoch); err == nil {
+			return dag.NewDatasetLookupFunc2048(c.hold(d))
+		}
+	}
+
+	datasetHasher := dag.datasetHasher()
 	lookup := func(index uint32) []uint32 {
-		return dag.generateDatasetItemUint(c.Cache(), index, 2, keccak512Hasher)
+		return dag.generateDatasetItemUint(c.Cache(), index, 4, datasetHasher)
 	}
 
 	return lookup
 }
 
-func (dag *DAG) NewLookupFunc2048(c *cache, epoch uint64) LookupFunc {
-	keccak512Hasher := crypto.NewKeccak512Hasher()
+// newDatasetLookupFunc returns a lookup reading items of size hash words
+// directly from the full dataset. The returned slices alias the dataset
+// and must not be modified.
+func newDatasetLookupFunc(d *dataset, size uint32) LookupFunc {
 	lookup := func(index uint32) []uint32 {
-		return dag.generateDatasetItemUint(c.Cache(), index, 4, keccak512Hasher)
+		start := uint64(index) * uint64(size*hashWords)
+		return d.Dataset()[start : start+uint64(size*hashWords)]
 	}
 
 	return lookup
 }
+
+func (dag *DAG) NewDatasetLookupFunc512(d *dataset) LookupFunc {
+	return newDatasetLookupFunc(d, 1)
+}
+
+func (dag *DAG) NewDatasetLookupFunc1024(d *dataset) LookupFunc {
+	return newDatasetLookupFunc(d, 2)
+}
+
+func (dag *DAG) NewDatasetLookupFunc2048(d *dataset) LookupFunc {
+	return newDatasetLookupFunc(d, 4)
+}
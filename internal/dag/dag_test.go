@@ -17,13 +17,33 @@
 package dag
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sencha-dev/powkit/internal/common"
 	"github.com/sencha-dev/powkit/internal/common/testutil"
+	"github.com/sencha-dev/powkit/powerr"
 )
 
+// waitBackground waits for the background generation started so far.
+func waitBackground(d *DAG) {
+	d.mu.Lock()
+	wg := d.wg
+	d.mu.Unlock()
+
+	if wg != nil {
+		wg.Wait()
+	}
+}
+
 func TestEpochNumber(t *testing.T) {
 	tests := []struct {
 		height uint64
@@ -96,6 +116,101 @@ func TestEpochNumber(t *testing.T) {
 	}
 }
 
+func TestEpochSchedule(t *testing.T) {
+	tests := []struct {
+		height      uint64
+		epoch       uint64
+		sizeEpoch   uint64
+		epochHeight uint64
+	}{
+		{
+			height:      0,
+			epoch:       0,
+			sizeEpoch:   0,
+			epochHeight: 0,
+		},
+		{
+			height:      30000,
+			epoch:       1,
+			sizeEpoch:   1,
+			epochHeight: 30000,
+		},
+		{
+			height:      11699999,
+			epoch:       389,
+			sizeEpoch:   389,
+			epochHeight: 11670000,
+		},
+		{
+			height:      11700000,
+			epoch:       390,
+			sizeEpoch:   195,
+			epochHeight: 11700000,
+		},
+		{
+			height:      11759999,
+			epoch:       390,
+			sizeEpoch:   195,
+			epochHeight: 11700000,
+		},
+		{
+			height:      11760000,
+			epoch:       391,
+			sizeEpoch:   196,
+			epochHeight: 11760000,
+		},
+		{
+			height:      12000000,
+			epoch:       395,
+			sizeEpoch:   200,
+			epochHeight: 12000000,
+		},
+	}
+
+	var d = &DAG{
+		Config: Config{
+			Name:       "ETC",
+			Revision:   23,
+			StorageDir: common.DefaultDir(".powcache"),
+
+			DatasetInitBytes:   1 << 30,
+			DatasetGrowthBytes: 1 << 23,
+			CacheInitBytes:     1 << 24,
+			CacheGrowthBytes:   1 << 17,
+
+			MixBytes:        128,
+			DatasetParents:  256,
+			EpochLength:     30000,
+			SeedEpochLength: 30000,
+			EpochSchedule:   []EpochSegment{{Height: 11700000, Length: 60000}},
+
+			CacheRounds:    3,
+			CachesCount:    3,
+			CachesLockMmap: false,
+		},
+	}
+
+	for i, tt := range tests {
+		epoch := d.CalcEpoch(tt.height)
+		if epoch != tt.epoch {
+			t.Errorf("failed on %d: epoch mismatch: have %d want %d", i, epoch, tt.epoch)
+		} else if sizeEpoch := d.sizeEpoch(epoch); sizeEpoch != tt.sizeEpoch {
+			t.Errorf("failed on %d: size epoch mismatch: have %d want %d", i, sizeEpoch, tt.sizeEpoch)
+		} else if chainEpoch := d.ChainEpoch(tt.height); chainEpoch != tt.sizeEpoch {
+			t.Errorf("failed on %d: chain epoch mismatch: have %d want %d", i, chainEpoch, tt.sizeEpoch)
+		} else if epochHeight := d.EpochHeight(epoch); epochHeight != tt.epochHeight {
+			t.Errorf("failed on %d: epoch height mismatch: have %d want %d", i, epochHeight, tt.epochHeight)
+		} else if size := d.CacheSize(epoch); size != d.calcCacheSize(tt.sizeEpoch) {
+			t.Errorf("failed on %d: cache size mismatch: have %d want %d", i, size, d.calcCacheSize(tt.sizeEpoch))
+		}
+	}
+
+	// the seed keeps advancing every 30000 blocks after the fork
+	if !reflect.DeepEqual(d.EpochSeed(391), d.SeedHash(11760000+1)) {
+		t.Errorf("seed mismatch after the fork")
+	}
+}
+
 func TestSeedHash(t *testing.T) {
 	tests := []struct {
 		epoch uint64
@@ -325,3 +440,909 @@ func TestCalcDatasetSize(t *testing.T) {
 		}
 	}
 }
+
+func TestFullDatasetLookup(t *testing.T) {
+	for _, storageDir := range []string{"", t.TempDir()} {
+		var d = New(Config{
+			Name:       "TEST",
+			Revision:   1,
+			StorageDir: storageDir,
+
+			DatasetInitBytes:   1 << 16,
+			DatasetGrowthBytes: 1 << 10,
+			CacheInitBytes:     1 << 12,
+			CacheGrowthBytes:   1 << 8,
+
+			MixBytes:        128,
+			DatasetParents:  256,
+			EpochLength:     100,
+			SeedEpochLength: 100,
+
+			CacheRounds:    3,
+			CachesCount:    3,
+			CachesLockMmap: false,
+
+			DatasetsCount:    1,
+			DatasetsLockMmap: false,
+		})
+
+		const epoch = 2
+		cache := d.GetCache(epoch)
+		lightLookups := []LookupFunc{
+			d.NewLookupFunc512(cache, epoch),
+			d.NewLookupFunc1024(cache, epoch),
+			d.NewLookupFunc2048(cache, epoch),
+		}
+
+		d.SetFullDataset(true)
+		fullLookups := []LookupFunc{
+			d.NewLookupFunc512(cache, epoch),
+			d.NewLookupFunc1024(cache, epoch),
+			d.NewLookupFunc2048(cache, epoch),
+		}
+
+		numItems := uint32(d.DatasetSize(epoch) / hashBytes)
+		for i := range lightLookups {
+			for index := uint32(0); index < numItems>>i; index += 7 {
+				light := lightLookups[i](index)
+				full := fullLookups[i](index)
+				if !reflect.DeepEqual(light, full) {
+					t.Errorf("failed on %q: lookup %d mismatch at %d: have %x, want %x", storageDir, i, index, full, light)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestGetCacheContext(t *testing.T) {
+	for _, storageDir := range []string{"", t.TempDir()} {
+		var mu sync.Mutex
+		var events []Event
+
+		cfg := Config{
+			Name:       "TEST",
+			Revision:   1,
+			StorageDir: storageDir,
+			OnEvent: func(event Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				if event.Epoch == 0 && event.Type != EventCacheProgress {
+					events = append(events, event)
+				}
+			},
+
+			DatasetInitBytes:   1 << 16,
+			DatasetGrowthBytes: 1 << 10,
+			CacheInitBytes:     1 << 12,
+			CacheGrowthBytes:   1 << 8,
+
+			MixBytes:        128,
+			DatasetParents:  256,
+			EpochLength:     100,
+			SeedEpochLength: 100,
+
+			CacheRounds:    3,
+			CachesCount:    1,
+			CachesLockMmap: false,
+		}
+
+		d := New(cfg)
+		defer d.Close()
+
+		// a cancelled context aborts the generation
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := d.GetCacheContext(ctx, 0); err != context.Canceled {
+			t.Errorf("failed on %q: have %v, want %v", storageDir, err, context.Canceled)
+		}
+
+		// a later call retries the generation
+		cache, err := d.GetCacheContext(context.Background(), 0)
+		if err != nil {
+			t.Errorf("failed on %q: %v", storageDir, err)
+			continue
+		}
+
+		expected := make([]uint32, d.CacheSize(0)/4)
+		d.generateCache(expected, 0, d.SeedHash(1))
+		if !reflect.DeepEqual(cache.Cache(), expected) {
+			t.Errorf("failed on %q: cache mismatch", storageDir)
+		}
+
+		types := []EventType{EventCacheStarted, EventCacheStarted}
+		if storageDir != "" {
+			// a new dag loads the cache from disk
+			loaded := New(cfg)
+			loaded.GetCache(0)
+			loaded.Close()
+			types = append(types, EventCacheWritten, EventCacheLoaded)
+		}
+
+		if epochs := d.CachedEpochs(); !reflect.DeepEqual(epochs, []uint64{0}) {
+			t.Errorf("failed on %q: cached epochs mismatch: have %v, want [0]", storageDir, epochs)
+		}
+
+		// only one cache is kept, so the next epoch evicts the first one
+		d.GetCache(2)
+		types = append(types, EventCacheEvicted)
+
+		if epochs := d.CachedEpochs(); !reflect.DeepEqual(epochs, []uint64{2}) {
+			t.Errorf("failed on %q: cached epochs mismatch: have %v, want [2]", storageDir, epochs)
+		}
+
+		mu.Lock()
+		if len(events) != len(types) {
+			t.Errorf("failed on %q: event count mismatch: have %d, want %d", storageDir, len(events), len(types))
+		} else {
+			for i, event := range events {
+				if event.Type != types[i] || event.Name != "TEST" {
+					t.Errorf("failed on %q: event %d mismatch: have %s, want %s", storageDir, i, event.Type, types[i])
+				}
+			}
+		}
+		mu.Unlock()
+	}
+}
+
+func TestStoredFiles(t *testing.T) {
+	storageDir := t.TempDir()
+	d := New(Config{
+		Name:       "TEST",
+		Revision:   2,
+		StorageDir: storageDir,
+
+		DatasetInitBytes:   1 << 16,
+		DatasetGrowthBytes: 1 << 10,
+		CacheInitBytes:     1 << 12,
+		CacheGrowthBytes:   1 << 8,
+
+		MixBytes:        128,
+		DatasetParents:  256,
+		EpochLength:     100,
+		SeedEpochLength: 100,
+
+		CacheRounds:    3,
+		CachesCount:    1,
+		CachesLockMmap: false,
+
+		L1Enabled:       true,
+		L1CacheSize:     1 << 8,
+		L1CacheNumItems: 1 << 6,
+	})
+	defer d.Close()
+
+	// pre-generating keeps every epoch despite CachesCount
+	if err := d.GenerateStoredCaches(context.Background(), 0, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// a file of an older revision and a leftover temporary file
+	stale := filepath.Join(storageDir, "cache-TEST-R1-0000000000000000")
+	for _, name := range []string{stale, filepath.Join(storageDir, "cache-TEST-R2-0000000000000000.123")} {
+		if err := os.WriteFile(name, make([]byte, 64), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := d.StoredFiles()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) != 9 {
+		t.Fatalf("file count mismatch: have %d, want 9", len(files))
+	} else if files[0].Path != stale || files[0].HasEpoch {
+		t.Errorf("stale file mismatch: have %+v", files[0])
+	}
+
+	for i, file := range files[1:] {
+		kind := []FileKind{FileCache, FileL1}[i%2]
+		if file.Kind != kind || !file.HasEpoch || file.Epoch != uint64(i/2) || file.Revision != 2 {
+			t.Errorf("failed on %d: have %s epoch %d (%t), want %s epoch %d", i, file.Kind, file.Epoch, file.HasEpoch, kind, i/2)
+		}
+	}
+
+	for epoch := uint64(0); epoch <= 3; epoch++ {
+		if err := d.VerifyStoredCache(context.Background(), epoch); err != nil {
+			t.Errorf("failed on %d: %v", epoch, err)
+		}
+	}
+
+	// flip a bit of the epoch 1 cache
+	path := files[3].Path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 1
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.VerifyStoredCache(context.Background(), 1); !errors.Is(err, powerr.ErrCorruptedCache) {
+		t.Errorf("corruption mismatch: have %v, want %v", err, powerr.ErrCorruptedCache)
+	}
+
+	if err := d.VerifyStoredCache(context.Background(), 4); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file mismatch: have %v, want %v", err, os.ErrNotExist)
+	}
+
+	// stale revisions are removed, then the oldest files until one epoch fits
+	removed, err := PruneStorage(storageDir, PruneOptions{StaleRevisions: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(removed) != 1 || removed[0].Path != stale {
+		t.Errorf("stale revision mismatch: have %+v", removed)
+	}
+
+	now := time.Now()
+	for i, file := range files[1:] {
+		os.Chtimes(file.Path, now, now.Add(time.Duration(i-len(files))*time.Minute))
+	}
+
+	budget := files[len(files)-2].Size + files[len(files)-1].Size
+	removed, err = PruneStorage(storageDir, PruneOptions{StaleRevisions: true, MaxBytes: budget})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(removed) != 7 {
+		t.Errorf("removed count mismatch: have %d, want 7", len(removed))
+	}
+
+	files, err = d.StoredFiles()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) != 2 || files[0].Epoch != 3 || files[1].Epoch != 3 {
+		t.Errorf("kept files mismatch: have %+v", files)
+	}
+}
+
+func TestCorruptedCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+	}{
+		{
+			name:    "truncated",
+			corrupt: func(data []byte) []byte { return data[:len(data)/2] },
+		},
+		{
+			name: "bit flip",
+			corrupt: func(data []byte) []byte {
+				data[len(data)-5] ^= 0x10
+				return data
+			},
+		},
+		{
+			name: "wrong epoch",
+			corrupt: func(data []byte) []byte {
+				data[16] = 1
+				return data
+			},
+		},
+		{
+			name: "previous format",
+			corrupt: func(data []byte) []byte {
+				return append(data[:8], data[headerWords*4:]...)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		// the look-ahead emits the events of the next epoch concurrently
+		var mu sync.Mutex
+		var events []Event
+		cfg := Config{
+			Name:       "TEST",
+			Revision:   1,
+			StorageDir: t.TempDir(),
+			OnEvent: func(event Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				if event.Epoch == 0 && (event.Type == EventCacheCorrupted || event.Type == EventCacheWritten) {
+					events = append(events, event)
+				}
+			},
+
+			DatasetInitBytes:   1 << 16,
+			DatasetGrowthBytes: 1 << 10,
+			CacheInitBytes:     1 << 12,
+			CacheGrowthBytes:   1 << 8,
+
+			MixBytes:        128,
+			DatasetParents:  256,
+			EpochLength:     100,
+			SeedEpochLength: 100,
+
+			CacheRounds:    3,
+			CachesCount:    3,
+			CachesLockMmap: false,
+		}
+
+		d := New(cfg)
+		if err := d.GenerateStoredCaches(context.Background(), 0, 0); err != nil {
+			t.Fatalf("failed on %s: %v", tt.name, err)
+		}
+
+		path := d.cacheStorageLocation(d.EpochSeed(0)[:8])
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed on %s: %v", tt.name, err)
+		} else if err := os.WriteFile(path, tt.corrupt(data), 0644); err != nil {
+			t.Fatalf("failed on %s: %v", tt.name, err)
+		}
+
+		if err := d.VerifyStoredCache(context.Background(), 0); !errors.Is(err, powerr.ErrCorruptedCache) {
+			t.Errorf("failed on %s: verification mismatch: have %v, want %v", tt.name, err, powerr.ErrCorruptedCache)
+		}
+
+		// loading the cache regenerates the file
+		mu.Lock()
+		events = nil
+		mu.Unlock()
+
+		cache := d.GetCache(0)
+		defer cache.Release()
+		d.Close()
+
+		expected := make([]uint32, d.CacheSize(0)/4)
+		d.generateCache(expected, 0, d.EpochSeed(0))
+		if !reflect.DeepEqual(cache.Cache(), expected) {
+			t.Errorf("failed on %s: cache mismatch", tt.name)
+		}
+
+		mu.Lock()
+		if len(events) < 2 || events[0].Type != EventCacheCorrupted || !errors.Is(events[0].Err, powerr.ErrCorruptedCache) ||
+			events[0].Path != path || events[1].Type != EventCacheWritten {
+			t.Errorf("failed on %s: events mismatch: have %+v", tt.name, events)
+		}
+		mu.Unlock()
+
+		if err := d.VerifyStoredCache(context.Background(), 0); err != nil {
+			t.Errorf("failed on %s: regenerated file: %v", tt.name, err)
+		}
+	}
+}
+
+func TestStorageLocking(t *testing.T) {
+	cfg := Config{
+		Name:       "TEST",
+		Revision:   1,
+		StorageDir: t.TempDir(),
+
+		DatasetInitBytes:   1 << 16,
+		DatasetGrowthBytes: 1 << 10,
+		CacheInitBytes:     1 << 12,
+		CacheGrowthBytes:   1 << 8,
+
+		MixBytes:        128,
+		DatasetParents:  256,
+		EpochLength:     100,
+		SeedEpochLength: 100,
+
+		CacheRounds:    3,
+		CachesCount:    3,
+		CachesLockMmap: false,
+	}
+
+	// another process generating the cache holds the generation lock
+	d := New(cfg)
+	path := d.cacheStorageLocation(d.EpochSeed(0)[:8])
+	unlock, err := lockGeneration(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	c := &cache{epoch: 0}
+	if err := c.generate(ctx, d); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("locked generation mismatch: have %v, want %v", err, context.DeadlineExceeded)
+	} else if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("locked generation wrote the cache: %v", err)
+	}
+	unlock()
+
+	if err := c.generate(context.Background(), d); err != nil {
+		t.Fatal(err)
+	}
+
+	// mapped files are in use and kept by pruning
+	opts := PruneOptions{MaxAge: time.Nanosecond}
+	if removed, err := PruneStorage(cfg.StorageDir, opts); err != nil {
+		t.Fatal(err)
+	} else if len(removed) != 0 {
+		t.Errorf("pruned %d files in use", len(removed))
+	} else if _, err := os.Stat(path); err != nil {
+		t.Errorf("pruned file in use: %v", err)
+	}
+
+	c.unmap()
+
+	if removed, err := PruneStorage(cfg.StorageDir, opts); err != nil {
+		t.Fatal(err)
+	} else if len(removed) != 1 || removed[0].Path != path {
+		t.Errorf("prune mismatch: have %+v", removed)
+	} else if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("prune kept the lock file: %v", err)
+	}
+}
+
+func TestLockGenerationRemoved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+
+	// a process removing the file holds its lock file
+	held, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	} else if locked, err := tryLockFile(held, true); err != nil || !locked {
+		t.Fatalf("failed to lock: %v", err)
+	}
+
+	done := make(chan func())
+	go func() {
+		unlock, err := lockGeneration(context.Background(), path)
+		if err != nil {
+			t.Error(err)
+			unlock = func() {}
+		}
+		done <- unlock
+	}()
+
+	// the waiting process gets the lock of the removed file, and must take
+	// the lock of the file at the path instead
+	time.Sleep(2 * lockPollInterval)
+	os.Remove(path + ".lock")
+	unlockFile(held)
+	held.Close()
+
+	unlock := <-done
+	defer unlock()
+
+	f, err := os.Open(path + ".lock")
+	if err != nil {
+		t.Fatalf("lock file not recreated: %v", err)
+	}
+	defer f.Close()
+
+	if locked, err := tryLockFile(f, true); err != nil {
+		t.Fatal(err)
+	} else if locked {
+		t.Errorf("lock file at the path not held")
+	}
+}
+
+func TestCacheLimits(t *testing.T) {
+	tests := []struct {
+		count     int
+		maxCaches uint64 // budget in caches of the largest size
+		lookAhead int
+		pinned    []uint64
+		epochs    []uint64
+		cached    []uint64
+		futures   []uint64
+	}{
+		{
+			count:   2,
+			epochs:  []uint64{0, 1, 2},
+			cached:  []uint64{1, 2},
+			futures: []uint64{3},
+		},
+		{
+			maxCaches: 2,
+			epochs:    []uint64{0, 1, 2},
+			cached:    []uint64{1, 2},
+			futures:   []uint64{3},
+		},
+		{
+			count:     3,
+			maxCaches: 1,
+			epochs:    []uint64{0, 1, 2},
+			cached:    []uint64{2},
+			futures:   []uint64{3},
+		},
+		{
+			count:     3,
+			lookAhead: 3,
+			epochs:    []uint64{5},
+			cached:    []uint64{5},
+			futures:   []uint64{6, 7, 8},
+		},
+		{
+			count:     3,
+			lookAhead: -1,
+			epochs:    []uint64{5, 6},
+			cached:    []uint64{5, 6},
+			futures:   []uint64{},
+		},
+		{
+			count:   3,
+			epochs:  []uint64{5, 2},
+			cached:  []uint64{2, 5},
+			futures: []uint64{6},
+		},
+		{
+			count:   2,
+			pinned:  []uint64{0},
+			epochs:  []uint64{1, 2, 3},
+			cached:  []uint64{0, 3},
+			futures: []uint64{4},
+		},
+	}
+
+	for i, tt := range tests {
+		cfg := Config{
+			Name:     "TEST",
+			Revision: 1,
+
+			DatasetInitBytes:   1 << 16,
+			DatasetGrowthBytes: 1 << 10,
+			CacheInitBytes:     1 << 14,
+			CacheGrowthBytes:   1 << 8,
+
+			MixBytes:        128,
+			DatasetParents:  256,
+			EpochLength:     100,
+			SeedEpochLength: 100,
+
+			CacheRounds:     3,
+			CachesCount:     tt.count,
+			CachesLookAhead: tt.lookAhead,
+			CachesLockMmap:  false,
+		}
+
+		d := New(cfg)
+		if tt.maxCaches > 0 {
+			d.SetCachesMaxBytes(tt.maxCaches*d.CacheSize(8) + d.CacheSize(0)/2)
+		}
+
+		for _, epoch := range tt.pinned {
+			if err := d.PinCache(context.Background(), epoch); err != nil {
+				t.Errorf("failed on %d: %v", i, err)
+			}
+		}
+
+		var refs []*cacheRef
+		for _, epoch := range tt.epochs {
+			refs = append(refs, d.GetCache(epoch))
+		}
+
+		// wait for the future caches, which the generating goroutine references
+		waitBackground(d)
+
+		if epochs := d.CachedEpochs(); !reflect.DeepEqual(epochs, tt.cached) {
+			t.Errorf("failed on %d: cached epochs mismatch: have %v, want %v", i, epochs, tt.cached)
+		}
+
+		d.mu.Lock()
+		futures := make([]uint64, 0)
+		for epoch := range d.futures {
+			futures = append(futures, epoch)
+		}
+		d.mu.Unlock()
+
+		sort.Slice(futures, func(i, j int) bool { return futures[i] < futures[j] })
+		if !reflect.DeepEqual(futures, tt.futures) {
+			t.Errorf("failed on %d: future epochs mismatch: have %v, want %v", i, futures, tt.futures)
+		}
+
+		// evicted caches stay usable until released
+		for j, ref := range refs {
+			if ref.Cache() == nil {
+				t.Errorf("failed on %d: cache of epoch %d released early", i, tt.epochs[j])
+			}
+			ref.Release()
+
+			evicted := true
+			for _, epoch := range tt.cached {
+				evicted = evicted && epoch != tt.epochs[j]
+			}
+
+			if released := ref.Cache() == nil; released != evicted {
+				t.Errorf("failed on %d: cache of epoch %d released mismatch: have %t, want %t", i, tt.epochs[j], released, evicted)
+			}
+		}
+
+		d.Close()
+		for _, ref := range refs {
+			if ref.Cache() != nil {
+				t.Errorf("failed on %d: cache of epoch %d not released on close", i, ref.epoch)
+			}
+		}
+	}
+}
+
+func TestSetEventHandler(t *testing.T) {
+	cfg := Config{
+		Name:     "TEST",
+		Revision: 1,
+
+		DatasetInitBytes:   1 << 16,
+		DatasetGrowthBytes: 1 << 10,
+		CacheInitBytes:     1 << 14,
+		CacheGrowthBytes:   1 << 8,
+
+		MixBytes:        128,
+		DatasetParents:  256,
+		EpochLength:     100,
+		SeedEpochLength: 100,
+
+		CacheRounds:     3,
+		CachesCount:     1,
+		CachesLookAhead: 4,
+		CachesLockMmap:  false,
+	}
+
+	var configured, replaced int32
+	cfg.OnEvent = func(Event) { atomic.AddInt32(&configured, 1) }
+
+	d := New(cfg)
+	d.GetCache(0).Release()
+
+	// the handler is replaced while the future caches are generated
+	d.SetEventHandler(func(event Event) {
+		if event.Type == EventCacheStarted {
+			atomic.AddInt32(&replaced, 1)
+		}
+	})
+	waitBackground(d)
+
+	before := atomic.LoadInt32(&configured)
+	d.GetCache(8).Release()
+	waitBackground(d)
+
+	if atomic.LoadInt32(&configured) != before {
+		t.Errorf("configured handler called after being replaced")
+	} else if atomic.LoadInt32(&replaced) == 0 {
+		t.Errorf("replaced handler not called")
+	}
+
+	d.Close()
+}
+
+func TestCloseLookAhead(t *testing.T) {
+	cfg := Config{
+		Name:     "TEST",
+		Revision: 1,
+
+		DatasetInitBytes:   1 << 16,
+		DatasetGrowthBytes: 1 << 10,
+		CacheInitBytes:     1 << 14,
+		CacheGrowthBytes:   1 << 8,
+
+		MixBytes:        128,
+		DatasetParents:  256,
+		EpochLength:     100,
+		SeedEpochLength: 100,
+
+		CacheRounds:    3,
+		CachesCount:    3,
+		CachesLockMmap: false,
+	}
+
+	var mu sync.Mutex
+	started := make(map[uint64]int)
+	cfg.OnEvent = func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if event.Type == EventCacheStarted {
+			started[event.Epoch]++
+		}
+	}
+
+	d := New(cfg)
+	d.GetCache(0).Release()
+	d.Close()
+
+	// the look-ahead keeps working after Close, and concurrent calls of
+	// Close do not race with it
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(epoch uint64) {
+			defer wg.Done()
+			d.GetCache(epoch).Release()
+			d.Close()
+		}(uint64(2 + i*2))
+	}
+	wg.Wait()
+
+	d.GetCache(10).Release()
+	waitBackground(d)
+	d.GetCache(11).Release()
+
+	mu.Lock()
+	if started[11] != 1 {
+		t.Errorf("future cache generated %d times, want 1", started[11])
+	}
+	mu.Unlock()
+
+	d.Close()
+}
+
+// closingStorage closes the DAG the first time a dataset is stored.
+type closingStorage struct {
+	Storage
+	dag  *DAG
+	once sync.Once
+}
+
+func (s *closingStorage) Store(ctx context.Context, entry Entry, generate func([]uint32) error) (Buffer, error) {
+	if entry.Kind == FileDataset {
+		s.once.Do(s.dag.Close)
+	}
+
+	return s.Storage.Store(ctx, entry, generate)
+}
+
+func TestCloseDataset(t *testing.T) {
+	d := New(Config{
+		Name:     "TEST",
+		Revision: 1,
+
+		DatasetInitBytes:   1 << 16,
+		DatasetGrowthBytes: 1 << 10,
+		CacheInitBytes:     1 << 12,
+		CacheGrowthBytes:   1 << 8,
+
+		MixBytes:        128,
+		DatasetParents:  256,
+		EpochLength:     100,
+		SeedEpochLength: 100,
+
+		CacheRounds:     3,
+		CachesCount:     3,
+		CachesLookAhead: -1,
+
+		DatasetsCount: 1,
+		FullDataset:   true,
+	})
+	d.Storage = &closingStorage{Storage: NewHeapStorage(), dag: d}
+
+	const epoch = 2
+	cache := d.GetCache(epoch)
+	defer cache.Release()
+
+	// the dataset build cancelled by Close falls back to the light cache
+	datasetHasher := d.datasetHasher()
+	lookup := d.NewLookupFunc512(cache, epoch)
+	for index := uint32(0); index < 16; index++ {
+		want := d.generateDatasetItemUint(cache.Cache(), index, 1, datasetHasher)
+		if have := lookup(index); !reflect.DeepEqual(have, want) {
+			t.Errorf("failed on %d: lookup mismatch: have %x, want %x", index, have, want)
+		}
+	}
+
+	if len(cache.datasets) != 0 {
+		t.Errorf("cancelled dataset held by the cache")
+	}
+
+	// the next call generates the dataset again
+	lookup = d.NewLookupFunc512(cache, epoch)
+	if len(cache.datasets) != 1 || cache.datasets[0].Dataset() == nil {
+		t.Fatalf("dataset not generated after Close")
+	}
+
+	want := d.generateDatasetItemUint(cache.Cache(), 7, 1, datasetHasher)
+	if have := lookup(7); !reflect.DeepEqual(have, want) {
+		t.Errorf("lookup mismatch: have %x, want %x", have, want)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	cfg := Config{
+		Name:     "TEST",
+		Revision: 1,
+
+		DatasetInitBytes:   1 << 16,
+		DatasetGrowthBytes: 1 << 10,
+		CacheInitBytes:     1 << 12,
+		CacheGrowthBytes:   1 << 8,
+
+		MixBytes:        128,
+		DatasetParents:  256,
+		EpochLength:     100,
+		SeedEpochLength: 100,
+
+		CacheRounds:     3,
+		CachesCount:     3,
+		CachesLookAhead: -1,
+		CachesLockMmap:  false,
+
+		L1Enabled:       true,
+		L1CacheSize:     64 * 4,
+		L1CacheNumItems: 64,
+	}
+
+	// pre-generate the files shipped in a read-only directory
+	prebuilt := t.TempDir()
+	shipped := cfg
+	shipped.StorageDir = prebuilt
+	if err := New(shipped).GenerateStoredCaches(context.Background(), 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		storage   Storage
+		generated bool
+		stored    bool
+	}{
+		{
+			name:      "heap",
+			storage:   NewHeapStorage(),
+			generated: true,
+		},
+		{
+			name:      "file",
+			storage:   NewFileStorage(t.TempDir()),
+			generated: true,
+			stored:    true,
+		},
+		{
+			name:      "huge pages",
+			storage:   NewHugePageStorage(),
+			generated: true,
+		},
+		{
+			name:    "read-only",
+			storage: NewReadOnlyStorage(prebuilt, nil),
+			stored:  true,
+		},
+		{
+			name:      "empty read-only",
+			storage:   NewReadOnlyStorage(t.TempDir(), nil),
+			generated: true,
+		},
+	}
+
+	base := New(cfg)
+	expected := make([]uint32, base.CacheSize(0)/4)
+	base.generateCache(expected, 0, base.EpochSeed(0))
+
+	expectedL1 := make([]uint32, cfg.L1CacheNumItems)
+	base.generateL1Cache(expectedL1, expected)
+
+	for _, tt := range tests {
+		var generated bool
+		var paths []string
+
+		c := cfg
+		c.Storage = tt.storage
+		c.OnEvent = func(event Event) {
+			generated = generated || event.Type == EventCacheStarted
+			if event.Path != "" {
+				paths = append(paths, event.Path)
+			}
+		}
+
+		var before []StoredFile
+		if dir, ok := tt.storage.(storageDir); ok {
+			before, _ = ListStorage(dir.Dir())
+		}
+
+		d := New(c)
+		cache := d.GetCache(0)
+		if !reflect.DeepEqual(cache.Cache(), expected) {
+			t.Errorf("failed on %s: cache mismatch", tt.name)
+		} else if !reflect.DeepEqual(cache.L1(), expectedL1) {
+			t.Errorf("failed on %s: l1 cache mismatch", tt.name)
+		}
+
+		if generated != tt.generated {
+			t.Errorf("failed on %s: generated mismatch: have %t, want %t", tt.name, generated, tt.generated)
+		} else if stored := len(paths) > 0; stored != tt.stored {
+			t.Errorf("failed on %s: stored mismatch: have %t, want %t", tt.name, stored, tt.stored)
+		}
+
+		// read-only storages never write to their directory
+		if _, ok := tt.storage.(readOnlyStorage); ok {
+			after, _ := ListStorage(tt.storage.(storageDir).Dir())
+			if len(after) != len(before) {
+				t.Errorf("failed on %s: wrote %d files", tt.name, len(after)-len(before))
+			}
+		}
+
+		cache.Release()
+		d.Close()
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dag
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type dataset struct {
+	epoch   uint64
+	once    sync.Once
+	err     error // Generation error, the DAG drops failed datasets
+	used    time.Time
+	refs    int // References held by the DAG and the caches, protected by the DAG lock
+	dataset Buffer
+}
+
+func (d *dataset) Dataset() []uint32 {
+	if d.dataset == nil {
+		return nil
+	}
+
+	return d.dataset.Data()
+}
+
+// generate ensures that the dataset content is generated before use. If the
+// context is cancelled or the dataset can be neither stored nor kept on the
+// heap, the error is returned to every caller of this dataset.
+func (d *dataset) generate(ctx context.Context, cfg *DAG) error {
+	d.once.Do(func() {
+		d.err = d.doGenerate(ctx, cfg)
+	})
+
+	return d.err
+}
+
+func (d *dataset) doGenerate(ctx context.Context, cfg *DAG) error {
+	size := cfg.DatasetSize(d.epoch)
+	seed := cfg.EpochSeed(d.epoch)
+	storage := cfg.storage()
+
+	// Try to load the dataset from the storage
+	var err error
+	entry := cfg.entry(FileDataset, d.epoch, seed, size, cfg.DatasetsLockMmap)
+	if d.dataset, err = cfg.load(storage, entry); err == nil {
+		return nil
+	}
+
+	// The full dataset is derived from the verification cache.
+	c, err := cfg.GetCacheContext(ctx, d.epoch)
+	if err != nil {
+		return err
+	}
+	defer c.Release()
+
+	generator := func(buffer []uint32) error {
+		return cfg.generateDatasetContext(ctx, buffer, c.Cache())
+	}
+	if d.dataset, err = cfg.store(ctx, storage, entry, generator); err != nil {
+		return err
+	}
+
+	if _, ok := storage.(storageDir); !ok {
+		return nil
+	}
+
+	// Iterate over all previous instances and delete old ones, unless other
+	// processes still have them mapped
+	for ep := int(d.epoch) - cfg.datasetsCount(); ep >= 0; ep-- {
+		seed := cfg.EpochSeed(uint64(ep))
+		storage.Remove(cfg.entry(FileDataset, uint64(ep), seed, 0, false))
+	}
+
+	return nil
+}
+
+// unmap releases the memory of the dataset once the last reference is
+// released.
+func (d *dataset) unmap() {
+	if d.dataset != nil {
+		d.dataset.Release()
+		d.dataset = nil
+	}
+}
@@ -0,0 +1,61 @@
+package dag
+
+type EventType int
+
+const (
+	EventCacheStarted   EventType = iota // Cache generation started
+	EventCacheProgress                   // Cache generation progressed, see Event.Percent
+	EventCacheWritten                    // Cache was generated and written to disk
+	EventCacheLoaded                     // Cache was loaded from an existing memory mapped file
+	EventCacheEvicted                    // Cache was evicted from memory
+	EventCacheCorrupted                  // Stored file failed validation and is regenerated, see Event.Err
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventCacheStarted:
+		return "started"
+	case EventCacheProgress:
+		return "progress"
+	case EventCacheWritten:
+		return "written"
+	case EventCacheLoaded:
+		return "loaded"
+	case EventCacheEvicted:
+		return "evicted"
+	case EventCacheCorrupted:
+		return "corrupted"
+	default:
+		return "unknown"
+	}
+}
+
+type Event struct {
+	Type    EventType
+	Name    string  // Name of the DAG config
+	Epoch   uint64  // Epoch of the cache
+	Percent float64 // Percentage of the generation done (only for EventCacheProgress)
+	Path    string  // Path of the file (only for EventCacheWritten, EventCacheLoaded and EventCacheCorrupted)
+	Err     error   // Validation error of the file (only for EventCacheCorrupted)
+}
+
+// SetEventHandler replaces the OnEvent handler of the config. Unlike setting
+// the field, it is safe while the caches are generated in the background.
+func (d *DAG) SetEventHandler(handler func(Event)) {
+	d.onEvent.Store(handler)
+}
+
+// emit sends an event to the event handler, if any.
+func (d *DAG) emit(event Event) {
+	handler := d.OnEvent
+	if value, ok := d.onEvent.Load().(func(Event)); ok {
+		handler = value
+	}
+
+	if handler == nil {
+		return
+	}
+
+	event.Name = d.Name
+	handler(event)
+}
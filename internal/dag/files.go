@@ -0,0 +1,347 @@
+package dag
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// maxResolvedEpoch is the number of epochs StoredFiles derives seeds for to
+// resolve the epochs of the files.
+const maxResolvedEpoch = 1 << 12
+
+// errNoStorage is returned by the storage methods of a DAG kept in memory.
+var errNoStorage = errors.New("no storage directory configured")
+
+// FileKind is the content of a stored file, as in the prefix of its name.
+type FileKind string
+
+const (
+	FileCache   FileKind = "cache"
+	FileL1      FileKind = "l1"
+	FileDataset FileKind = "full"
+)
+
+// StoredFile is a cache, L1 cache or full dataset file in a storage directory,
+// named <kind>-<Name>-R<Revision>-<seed>.
+type StoredFile struct {
+	Path     string
+	Kind     FileKind
+	Name     string
+	Revision int
+	Seed     []byte // First 8 bytes of the epoch seed
+	Epoch    uint64 // Only valid if HasEpoch is set
+	HasEpoch bool   // Whether the epoch was resolved from the seed, see StoredFiles
+	Size     int64
+	ModTime  time.Time
+}
+
+func parseFileName(name string) (StoredFile, bool) {
+	var file StoredFile
+
+	first := strings.IndexByte(name, '-')
+	last := strings.LastIndexByte(name, '-')
+	if first < 0 || last <= first {
+		return file, false
+	}
+
+	file.Kind = FileKind(name[:first])
+	switch file.Kind {
+	case FileCache, FileL1, FileDataset:
+	default:
+		return file, false
+	}
+
+	// temporary files have a random suffix and fail to decode
+	seed, err := hex.DecodeString(name[last+1:])
+	if err != nil || len(seed) != 8 {
+		return file, false
+	}
+	file.Seed = seed
+
+	middle := name[first+1 : last]
+	sep := strings.LastIndex(middle, "-R")
+	if sep < 1 {
+		return file, false
+	}
+
+	file.Revision, err = strconv.Atoi(middle[sep+2:])
+	if err != nil {
+		return file, false
+	}
+	file.Name = middle[:sep]
+
+	return file, true
+}
+
+// ListStorage returns the cache, L1 cache and dataset files in a storage
+// directory, ordered by file name. Their epochs are not resolved, since that
+// requires the config of each name. A missing directory is empty.
+func ListStorage(dir string) ([]StoredFile, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var files []StoredFile
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		file, ok := parseFileName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		file.Path = filepath.Join(dir, entry.Name())
+		file.Size = info.Size()
+		file.ModTime = info.ModTime()
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// seedEpochs maps the seed prefixes used in file names to the epochs up to
+// maxEpoch, walking the seed chain once.
+func (d *DAG) seedEpochs(maxEpoch uint64) map[string]uint64 {
+	epochs := make(map[string]uint64)
+	seedHasher := d.seedHasher()
+	seed := make([]byte, 32)
+
+	var index uint64
+	for epoch := uint64(0); epoch <= maxEpoch; epoch++ {
+		// see SeedHash, which hashes once per seed epoch
+		for target := (d.EpochHeight(epoch) + 1) / d.SeedEpochLength; index < target; index++ {
+			seedHasher(seed, seed)
+		}
+		epochs[string(seed[:8])] = epoch
+	}
+
+	return epochs
+}
+
+// StoredFiles returns the files of the config in the storage directory,
+// ordered by revision, epoch and kind. The epochs of the files of the current
+// revision are resolved from their seeds, up to epoch maxResolvedEpoch.
+func (d *DAG) StoredFiles() ([]StoredFile, error) {
+	dir := d.storageDir()
+	if dir == "" {
+		return nil, nil
+	}
+
+	all, err := ListStorage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var epochs map[string]uint64
+	var files []StoredFile
+	for _, file := range all {
+		if file.Name != d.Name {
+			continue
+		}
+
+		if file.Revision == d.Revision {
+			if epochs == nil {
+				epochs = d.seedEpochs(maxResolvedEpoch)
+			}
+			file.Epoch, file.HasEpoch = epochs[string(file.Seed)]
+		}
+		files = append(files, file)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Revision != files[j].Revision {
+			return files[i].Revision < files[j].Revision
+		} else if files[i].Epoch != files[j].Epoch {
+			return files[i].Epoch < files[j].Epoch
+		}
+
+		return files[i].Kind < files[j].Kind
+	})
+
+	return files, nil
+}
+
+// verifyFile validates a stored file and compares it with the content it
+// should hold.
+func verifyFile(path string, header fileHeader, expected []uint32) error {
+	df, err := memoryMap(path, false, header)
+	if err != nil {
+		return err
+	}
+	defer df.Release()
+
+	if len(df.data) != len(expected) {
+		return fmt.Errorf("%w: %s: have %d bytes, want %d", powerr.ErrCorruptedCache, path, len(df.data)*4, len(expected)*4)
+	}
+
+	for i := range expected {
+		if df.data[i] != expected[i] {
+			return fmt.Errorf("%w: %s: mismatch at byte %d", powerr.ErrCorruptedCache, path, i*4)
+		}
+	}
+
+	return nil
+}
+
+// VerifyStoredCache compares the stored cache of the epoch, and its L1 cache
+// if enabled, with a freshly generated copy. The error matches
+// powerr.ErrCorruptedCache if they differ and os.ErrNotExist if a file is
+// missing.
+func (d *DAG) VerifyStoredCache(ctx context.Context, epoch uint64) error {
+	if d.storageDir() == "" {
+		return errNoStorage
+	}
+
+	seed := d.EpochSeed(epoch)
+	expected := make([]uint32, d.CacheSize(epoch)/4)
+	if err := d.generateCacheContext(ctx, expected, epoch, seed, nil); err != nil {
+		return err
+	}
+
+	header := d.entry(FileCache, epoch, seed, d.CacheSize(epoch), false).header()
+	if err := verifyFile(d.cacheStorageLocation(seed[:8]), header, expected); err != nil {
+		return err
+	}
+
+	if d.L1Enabled {
+		l1 := make([]uint32, d.L1CacheNumItems)
+		d.generateL1Cache(l1, expected)
+
+		header := d.entry(FileL1, epoch, seed, d.L1CacheSize, false).header()
+		return verifyFile(d.l1StorageLocation(seed[:8]), header, l1)
+	}
+
+	return nil
+}
+
+// GenerateStoredCaches writes the caches of the epochs from first to last
+// inclusive that are not stored yet, without keeping them in memory or
+// removing the files of older epochs.
+func (d *DAG) GenerateStoredCaches(ctx context.Context, first, last uint64) error {
+	dir := d.storageDir()
+	if dir == "" {
+		return errNoStorage
+	}
+
+	for epoch := first; epoch <= last && epoch >= first; epoch++ {
+		c := &cache{epoch: epoch, keepOlder: true}
+		err := c.generate(ctx, d)
+
+		// generation falls back to memory if the file cannot be written
+		stored := c.cache != nil && c.cache.Path() != ""
+		c.unmap()
+
+		if err != nil {
+			return err
+		} else if !stored {
+			return fmt.Errorf("failed to store the cache of epoch %d in %s", epoch, dir)
+		}
+	}
+
+	return nil
+}
+
+// PruneOptions selects the files PruneStorage removes. Files matching any of
+// the options are removed.
+type PruneOptions struct {
+	MaxAge         time.Duration // Remove the files not modified for longer, if positive
+	MaxBytes       int64         // Remove the least recently modified files until the rest fit, if positive
+	StaleRevisions bool          // Remove the files of a name that also has files of a higher revision
+	DryRun         bool          // Only return the files that would be removed
+}
+
+// PruneStorage removes files from a storage directory according to the options
+// and returns the removed files. Files mapped by a process are in use and kept,
+// the MaxBytes budget is then met by removing the next least recently modified
+// files instead.
+func PruneStorage(dir string, opts PruneOptions) ([]StoredFile, error) {
+	files, err := ListStorage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make(map[string]int)
+	for _, file := range files {
+		if revision, ok := revisions[file.Name]; !ok || file.Revision > revision {
+			revisions[file.Name] = file.Revision
+		}
+	}
+
+	remove := func(file StoredFile) (bool, error) {
+		if opts.DryRun {
+			return !fileInUse(file.Path), nil
+		}
+
+		return removeFile(file.Path)
+	}
+
+	var kept, removed []StoredFile
+	now := time.Now()
+	for _, file := range files {
+		switch {
+		case opts.StaleRevisions && file.Revision < revisions[file.Name]:
+		case opts.MaxAge > 0 && now.Sub(file.ModTime) > opts.MaxAge:
+		default:
+			kept = append(kept, file)
+			continue
+		}
+
+		ok, err := remove(file)
+		if err != nil {
+			return removed, err
+		} else if ok {
+			removed = append(removed, file)
+		} else {
+			kept = append(kept, file)
+		}
+	}
+
+	if opts.MaxBytes > 0 {
+		sort.SliceStable(kept, func(i, j int) bool {
+			return kept[i].ModTime.Before(kept[j].ModTime)
+		})
+
+		var total int64
+		for _, file := range kept {
+			total += file.Size
+		}
+
+		for _, file := range kept {
+			if total <= opts.MaxBytes {
+				break
+			}
+
+			ok, err := remove(file)
+			if err != nil {
+				return removed, err
+			} else if ok {
+				total -= file.Size
+				removed = append(removed, file)
+			}
+		}
+	}
+
+	return removed, nil
+}
@@ -17,14 +17,29 @@
 package dag
 
 import (
+	"context"
 	"encoding/binary"
-	"reflect"
+	"runtime"
+	"sync"
 	"unsafe"
 
 	"github.com/sencha-dev/powkit/internal/common/bitutil"
 	"github.com/sencha-dev/powkit/internal/crypto"
 )
 
+// progressRows is the number of cache rows generated between
+// cancellation checks and progress reports.
+const progressRows = 1 << 14
+
+// uint32sAsBytes returns a byte view over the memory of a uint32 slice.
+func uint32sAsBytes(data []uint32) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return unsafe.Slice((*byte)(unsafe.Pointer(&data[0])), len(data)*4)
+}
+
 // generateCache creates a verification cache of a given size for an input seed.
 // The cache production process involves first sequentially filling up 32 MB of
 // memory, then performing two passes of Sergio Demian Lerner's RandMemoHash
@@ -32,23 +47,48 @@ import (
 // set of 524288 64-byte values.
 // This method places the result into dest in machine byte order.
 func (d *DAG) generateCache(dest []uint32, epoch uint64, seed []byte) {
+	d.generateCacheContext(context.Background(), dest, epoch, seed, nil)
+}
+
+// generateCacheContext is generateCache with support for cancellation and
+// progress reporting. The context is checked every progressRows rows and,
+// if set, progress is called with the percentage done.
+func (d *DAG) generateCacheContext(ctx context.Context, dest []uint32, epoch uint64, seed []byte, progress func(float64)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Convert our destination slice to a byte buffer
-	header := *(*reflect.SliceHeader)(unsafe.Pointer(&dest))
-	header.Len *= 4
-	header.Cap *= 4
-	cache := *(*[]byte)(unsafe.Pointer(&header))
+	cache := uint32sAsBytes(dest)
 
 	// Calculate the number of theoretical rows (we'll store in one buffer nonetheless)
 	size := uint64(len(cache))
 	rows := int(size) / hashBytes
 
+	// Check for cancellation and report progress periodically
+	total := rows * (1 + d.CacheRounds)
+	step := func(done int) error {
+		if done%progressRows != 0 {
+			return nil
+		} else if err := ctx.Err(); err != nil {
+			return err
+		} else if progress != nil {
+			progress(100 * float64(done) / float64(total))
+		}
+
+		return nil
+	}
+
 	// Create a hasher to reuse between invocations
-	keccak512Hasher := crypto.NewKeccak512Hasher()
+	cacheHasher := d.cacheHasher(epoch)
 
 	// Sequentially produce the initial dataset
-	keccak512Hasher(cache, seed)
+	cacheHasher(cache, seed)
 	for offset := uint64(hashBytes); offset < size; offset += hashBytes {
-		keccak512Hasher(cache[offset:], cache[offset-hashBytes:offset])
+		cacheHasher(cache[offset:], cache[offset-hashBytes:offset])
+		if err := step(int(offset / hashBytes)); err != nil {
+			return err
+		}
 	}
 
 	// Use a low-round version of randmemohash
@@ -62,31 +102,89 @@ func (d *DAG) generateCache(dest []uint32, epoch uint64, seed []byte) {
 				xorOff = (binary.LittleEndian.Uint32(cache[dstOff:]) % uint32(rows)) * hashBytes
 			)
 			bitutil.XORBytes(temp, cache[srcOff:srcOff+hashBytes], cache[xorOff:xorOff+hashBytes])
-			keccak512Hasher(cache[dstOff:], temp)
+			cacheHasher(cache[dstOff:], temp)
+			if err := step((i+1)*rows + j); err != nil {
+				return err
+			}
 		}
 	}
+
+	if progress != nil {
+		progress(100)
+	}
+
+	return nil
 }
 
 func (d *DAG) generateL1Cache(dest []uint32, cache []uint32) {
-	keccak512Hasher := crypto.NewKeccak512Hasher()
+	datasetHasher := d.datasetHasher()
 
-	header := *(*reflect.SliceHeader)(unsafe.Pointer(&dest))
-	header.Len *= 4
-	header.Cap *= 4
-	l1 := *(*[]byte)(unsafe.Pointer(&header))
+	l1 := uint32sAsBytes(dest)
 
 	size := uint64(len(l1))
 	rows := int(size) / hashBytes
 
 	for i := 0; i < rows; i++ {
-		item := d.generateDatasetItem(cache, uint32(i), keccak512Hasher)
+		item := d.generateDatasetItem(cache, uint32(i), datasetHasher)
 		copy(l1[i*hashBytes:], item)
 	}
 }
 
+// generateDataset generates the entire ethash dataset for mining.
+// This method places the result into dest in machine byte order.
+func (d *DAG) generateDataset(dest []uint32, cache []uint32) {
+	d.generateDatasetContext(context.Background(), dest, cache)
+}
+
+// generateDatasetContext is generateDataset with support for cancellation,
+// the context is checked every progressRows rows of each thread.
+func (d *DAG) generateDatasetContext(ctx context.Context, dest []uint32, cache []uint32) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dataset := uint32sAsBytes(dest)
+	rows := len(dataset) / hashBytes
+
+	// Generate the dataset on many goroutines since it takes a while
+	threads := runtime.NumCPU()
+	batch := (rows + threads - 1) / threads
+
+	var pend sync.WaitGroup
+	pend.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func(id int) {
+			defer pend.Done()
+
+			// Create a hasher to reuse between invocations
+			datasetHasher := d.datasetHasher()
+
+			// Calculate the data segment this thread should generate
+			first := id * batch
+			limit := first + batch
+			if limit > rows {
+				limit = rows
+			}
+
+			for index := first; index < limit; index++ {
+				if (index-first)%progressRows == 0 && ctx.Err() != nil {
+					return
+				}
+
+				item := d.generateDatasetItem(cache, uint32(index), datasetHasher)
+				copy(dataset[index*hashBytes:], item)
+			}
+		}(i)
+	}
+
+	pend.Wait()
+
+	return ctx.Err()
+}
+
 // generateDatasetItem combines data from 256 pseudorandomly selected cache nodes,
 // and hashes that to compute a single dataset node.
-func (d *DAG) generateDatasetItem(cache []uint32, index uint32, keccak512Hasher crypto.Hasher) []byte {
+func (d *DAG) generateDatasetItem(cache []uint32, index uint32, datasetHasher crypto.Hasher) []byte {
 	// Calculate the number of theoretical rows (we use one buffer nonetheless)
 	rows := uint32(len(cache) / hashWords)
 
@@ -98,7 +196,7 @@ func (d *DAG) generateDatasetItem(cache []uint32, index uint32, keccak512Hasher
 		binary.LittleEndian.PutUint32(mix[i*4:], cache[(index%rows)*hashWords+uint32(i)])
 	}
 
-	keccak512Hasher(mix, mix)
+	datasetHasher(mix, mix)
 
 	// Convert the mix to uint32s to avoid constant bit shifting
 	intMix := make([]uint32, hashWords)
@@ -117,15 +215,15 @@ func (d *DAG) generateDatasetItem(cache []uint32, index uint32, keccak512Hasher
 		binary.LittleEndian.PutUint32(mix[i*4:], val)
 	}
 
-	keccak512Hasher(mix, mix)
+	datasetHasher(mix, mix)
 
 	return mix
 }
 
-func (d *DAG) generateDatasetItemUint(cache []uint32, index, size uint32, keccak512Hasher crypto.Hasher) []uint32 {
+func (d *DAG) generateDatasetItemUint(cache []uint32, index, size uint32, datasetHasher crypto.Hasher) []uint32 {
 	data := make([]uint32, hashWords*size)
 	for n := 0; n < int(size); n++ {
-		item := d.generateDatasetItem(cache, index*size+uint32(n), keccak512Hasher)
+		item := d.generateDatasetItem(cache, index*size+uint32(n), datasetHasher)
 
 		for i := 0; i < hashWords; i++ {
 			data[n*hashWords+i] = binary.LittleEndian.Uint32(item[i*4:])
@@ -230,3 +230,44 @@ func TestDatasetItemGeneration(t *testing.T) {
 		}
 	}
 }
+
+func TestDatasetGeneration(t *testing.T) {
+	var d = &DAG{
+		Config: Config{
+			Name:     "TEST",
+			Revision: 1,
+
+			DatasetInitBytes:   1 << 16,
+			DatasetGrowthBytes: 1 << 10,
+			CacheInitBytes:     1 << 12,
+			CacheGrowthBytes:   1 << 8,
+
+			MixBytes:        128,
+			DatasetParents:  256,
+			EpochLength:     100,
+			SeedEpochLength: 100,
+
+			CacheRounds: 3,
+			CachesCount: 3,
+		},
+	}
+
+	for _, epoch := range []uint64{0, 3} {
+		cache := make([]uint32, d.CacheSize(epoch)/4)
+		seed := d.SeedHash(epoch*d.EpochLength + 1)
+		d.generateCache(cache, epoch, seed)
+
+		dataset := make([]uint32, d.DatasetSize(epoch)/4)
+		d.generateDataset(dataset, cache)
+
+		keccak512Hasher := crypto.NewKeccak512Hasher()
+		raw := convutil.Uint32ArrayToBytes(dataset, binary.LittleEndian)
+		for index := 0; index < len(raw)/hashBytes; index++ {
+			item := d.generateDatasetItem(cache, uint32(index), keccak512Hasher)
+			if !reflect.DeepEqual(raw[index*hashBytes:(index+1)*hashBytes], item) {
+				t.Errorf("failed on epoch %d: item %d mismatch", epoch, index)
+				break
+			}
+		}
+	}
+}
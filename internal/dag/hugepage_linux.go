@@ -0,0 +1,91 @@
+//go:build linux
+// +build linux
+
+package dag
+
+import (
+	"context"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// hugePageSize is the size of the default huge pages on x86-64 and arm64,
+// anonymous mappings backed by them are rounded up to it.
+const hugePageSize = 2 << 20
+
+type hugePageBuffer struct {
+	mem  []byte
+	data []uint32
+}
+
+func (b *hugePageBuffer) Data() []uint32 { return b.data }
+func (b *hugePageBuffer) Path() string   { return "" }
+
+func (b *hugePageBuffer) Release() error {
+	if b.mem == nil {
+		return nil
+	}
+
+	err := unix.Munmap(b.mem)
+	b.mem, b.data = nil, nil
+
+	return err
+}
+
+type hugePageStorage struct{}
+
+// NewHugePageStorage returns a storage keeping the data in anonymous memory
+// backed by huge pages, which reduces TLB misses on the random accesses of
+// dataset generation and hashing. It uses the reserved huge pages if any are
+// available and transparent huge pages otherwise. The data is generated on
+// every use.
+func NewHugePageStorage() Storage {
+	return hugePageStorage{}
+}
+
+func (hugePageStorage) Load(entry Entry) (Buffer, error) {
+	return nil, os.ErrNotExist
+}
+
+func (hugePageStorage) Store(ctx context.Context, entry Entry, generate func([]uint32) error) (Buffer, error) {
+	size := int((entry.Size + hugePageSize - 1) / hugePageSize * hugePageSize)
+	if size == 0 {
+		size = hugePageSize
+	}
+
+	prot := unix.PROT_READ | unix.PROT_WRITE
+	flags := unix.MAP_PRIVATE | unix.MAP_ANONYMOUS
+	mem, err := unix.Mmap(-1, 0, size, prot, flags|unix.MAP_HUGETLB)
+	if err != nil {
+		// no reserved huge pages, fall back to transparent ones
+		if mem, err = unix.Mmap(-1, 0, size, prot, flags); err != nil {
+			return nil, err
+		}
+		unix.Madvise(mem, unix.MADV_HUGEPAGE)
+	}
+
+	buf := &hugePageBuffer{
+		mem:  mem,
+		data: unsafe.Slice((*uint32)(unsafe.Pointer(&mem[0])), entry.Size/4),
+	}
+
+	if entry.LockMemory {
+		if err := unix.Mlock(mem); err != nil {
+			buf.Release()
+			return nil, err
+		}
+	}
+
+	if err := generate(buf.data); err != nil {
+		buf.Release()
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (hugePageStorage) Remove(entry Entry) error {
+	return nil
+}
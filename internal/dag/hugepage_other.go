@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package dag
+
+// NewHugePageStorage returns a storage keeping the data in anonymous memory
+// backed by huge pages. Huge pages are only supported on Linux, elsewhere the
+// data is kept on the heap.
+func NewHugePageStorage() Storage {
+	return NewHeapStorage()
+}
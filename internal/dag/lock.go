@@ -0,0 +1,154 @@
+package dag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockPollInterval is how often a blocked lock is retried.
+const lockPollInterval = 50 * time.Millisecond
+
+// lockFileContext acquires an advisory lock on the file, retrying until it is
+// available or the context is done.
+func lockFileContext(ctx context.Context, f *os.File, exclusive bool) error {
+	for {
+		locked, err := tryLockFile(f, exclusive)
+		if err != nil || locked {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// lockGeneration acquires the exclusive lock on path.lock, which is held
+// while a process loads or generates the file at path so that the processes
+// sharing a storage directory generate it only once. If the lock file cannot
+// be created, such as in a read-only directory, the file is not locked and
+// only the error of the context is returned.
+func lockGeneration(ctx context.Context, path string) (func(), error) {
+	unlocked := func() {}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return unlocked, nil
+	}
+
+	for {
+		f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return unlocked, nil
+		}
+
+		if err := lockFileContext(ctx, f, true); err != nil {
+			f.Close()
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			return unlocked, nil
+		}
+
+		// the lock file may have been removed by removeLockFile while waiting
+		// for it, in which case another process can create and lock a new one
+		// at the same path, so the lock is taken again
+		if isLinked(f, path+".lock") {
+			unlock := func() {
+				unlockFile(f)
+				f.Close()
+			}
+
+			return unlock, nil
+		}
+
+		unlockFile(f)
+		f.Close()
+	}
+}
+
+// isLinked reports whether the open file is still the file at path, assuming
+// it is if either cannot be checked.
+func isLinked(f *os.File, path string) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return true
+	}
+
+	pathInfo, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false
+	} else if err != nil {
+		return true
+	}
+
+	return os.SameFile(info, pathInfo)
+}
+
+// fileInUse reports whether another process holds the file mapped, since
+// mapped files are shared locked for as long as they are mapped.
+func fileInUse(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	locked, err := tryLockFile(f, true)
+	if err != nil || !locked {
+		return err == nil
+	}
+	unlockFile(f)
+
+	return false
+}
+
+// removeFile removes a stored file and its generation lock file, unless a
+// process holds the file mapped, in which case false is returned.
+func removeFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	locked, err := tryLockFile(f, true)
+	if err != nil || !locked {
+		f.Close()
+		return false, err
+	}
+
+	// the file is removed while locked so that no process maps it in between,
+	// except on Windows which does not remove open files
+	err = os.Remove(path)
+	unlockFile(f)
+	f.Close()
+	if err != nil && !os.IsNotExist(err) {
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	removeLockFile(path + ".lock")
+
+	return true, nil
+}
+
+// removeLockFile removes a generation lock file unless a process holds it. A
+// process waiting for the removed file takes the lock again on a new one, see
+// lockGeneration.
+func removeLockFile(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	if locked, err := tryLockFile(f, true); err == nil && locked {
+		os.Remove(path)
+		unlockFile(f)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package dag
+
+import (
+	"os"
+)
+
+// tryLockFile always succeeds on platforms without file locking, where
+// processes sharing a storage directory are not coordinated.
+func tryLockFile(f *os.File, exclusive bool) (bool, error) {
+	return true, nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
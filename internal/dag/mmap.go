@@ -17,7 +17,11 @@
 package dag
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"hash/crc32"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -26,53 +30,156 @@ import (
 	"unsafe"
 
 	"github.com/edsrzf/mmap-go"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+const (
+	// fileVersion is the version of the file format, bumped on layout changes.
+	fileVersion = 1
+
+	// headerWords is the size of the file header in 32 bit words, including
+	// the dump magic. It keeps the data that follows aligned.
+	headerWords = 32
 )
 
 var (
 	dumpMagic = []uint32{0xbaddcafe, 0xfee1dead}
+
+	castagnoli = crc32.MakeTable(crc32.Castagnoli)
 )
 
+// fileHeader identifies the content of a stored file. After the dump magic, the
+// header holds the format version, a CRC-32C checksum of the data, the epoch,
+// the data size in bytes, the epoch seed and the config name, all little endian.
+type fileHeader struct {
+	name  string
+	epoch uint64
+	seed  []byte
+	size  uint64
+}
+
+func (h fileHeader) encode(header []byte, checksum uint32) {
+	binary.LittleEndian.PutUint32(header[8:], fileVersion)
+	binary.LittleEndian.PutUint32(header[12:], checksum)
+	binary.LittleEndian.PutUint64(header[16:], h.epoch)
+	binary.LittleEndian.PutUint64(header[24:], h.size)
+	copy(header[32:64], h.seed)
+	copy(header[64:96], h.name)
+}
+
+// validate checks the header and the data of a mapped file.
+func (h fileHeader) validate(buffer []uint32) error {
+	if len(buffer) < headerWords {
+		return errors.New("truncated header")
+	}
+
+	for i, magic := range dumpMagic {
+		if buffer[i] != magic {
+			return errors.New("invalid dump magic")
+		}
+	}
+
+	header := uint32sAsBytes(buffer[:headerWords])
+	expected := make([]byte, len(header))
+	h.encode(expected, 0)
+
+	data := uint32sAsBytes(buffer[headerWords:])
+	switch version := binary.LittleEndian.Uint32(header[8:]); {
+	case version != fileVersion:
+		return fmt.Errorf("format version %d, want %d", version, fileVersion)
+	case !bytes.Equal(header[64:96], expected[64:96]):
+		return fmt.Errorf("name %q, want %q", bytes.TrimRight(header[64:96], "\x00"), h.name)
+	case !bytes.Equal(header[16:24], expected[16:24]):
+		return fmt.Errorf("epoch %d, want %d", binary.LittleEndian.Uint64(header[16:]), h.epoch)
+	case !bytes.Equal(header[32:64], expected[32:64]):
+		return fmt.Errorf("seed %x, want %x", header[32:64], h.seed)
+	case !bytes.Equal(header[24:32], expected[24:32]):
+		return fmt.Errorf("size %d, want %d", binary.LittleEndian.Uint64(header[24:]), h.size)
+	case uint64(len(data)) != h.size:
+		return fmt.Errorf("truncated to %d bytes, want %d", len(data), h.size)
+	case crc32.Checksum(data, castagnoli) != binary.LittleEndian.Uint32(header[12:]):
+		return errors.New("checksum mismatch")
+	}
+
+	return nil
+}
+
+// dataFile is a memory mapped file, holding a shared lock on it while mapped.
 type dataFile struct {
+	path string
 	dump *os.File
 	mmap mmap.MMap
 	data []uint32
 }
 
-// memoryMap tries to memory map a file of uint32s for read only access.
-func memoryMap(path string, lock bool) (dataFile, error) {
-	var df dataFile
+func (df *dataFile) Data() []uint32 {
+	return df.data
+}
 
+func (df *dataFile) Path() string {
+	return df.path
+}
+
+// Release unmaps the memory and closes the file, which releases its lock.
+func (df *dataFile) Release() error {
+	if df.mmap == nil {
+		return nil
+	}
+
+	err := df.mmap.Unmap()
+	if closeErr := df.dump.Close(); err == nil {
+		err = closeErr
+	}
+	df.mmap, df.dump, df.data = nil, nil, nil
+
+	return err
+}
+
+// memoryMap tries to memory map a file of uint32s for read only access. Files
+// failing validation against the header return an error matching
+// powerr.ErrCorruptedCache. The file is shared locked while mapped so that
+// other processes do not remove it, and files being removed return an error
+// matching os.ErrNotExist.
+func memoryMap(path string, lock bool, header fileHeader) (*dataFile, error) {
 	file, err := os.OpenFile(path, os.O_RDONLY, 0644)
 	if err != nil {
-		return df, err
+		return nil, err
+	}
+
+	if locked, err := tryLockFile(file, false); err != nil {
+		file.Close()
+		return nil, err
+	} else if !locked {
+		file.Close()
+		return nil, fmt.Errorf("%s is being removed: %w", path, os.ErrNotExist)
 	}
 
 	mem, buffer, err := memoryMapFile(file, false)
 	if err != nil {
 		file.Close()
-		return df, err
+		return nil, err
 	}
 
-	for i, magic := range dumpMagic {
-		if buffer[i] != magic {
-			mem.Unmap()
-			file.Close()
-			return df, errors.New("invalid dump magic")
-		}
+	if err := header.validate(buffer); err != nil {
+		mem.Unmap()
+		file.Close()
+		return nil, fmt.Errorf("%w: %s: %v", powerr.ErrCorruptedCache, path, err)
 	}
 
 	if lock {
 		if err := mem.Lock(); err != nil {
 			mem.Unmap()
 			file.Close()
-			return df, errors.New("invalid dump magic")
+			return nil, err
 		}
 	}
 
-	df = dataFile{
+	df := &dataFile{
+		path: path,
 		dump: file,
 		mmap: mem,
-		data: buffer[len(dumpMagic):],
+		data: buffer[headerWords:],
 	}
 
 	return df, nil
@@ -114,26 +221,24 @@ func ensureSize(f *os.File, size int64) error {
 
 // memoryMapAndGenerate tries to memory map a temporary file of uint32s for write
 // access, fill it with the data from a generator and then move it into the final
-// path requested.
-func memoryMapAndGenerate(path string, size uint64, lock bool, generator func(buffer []uint32)) (dataFile, error) {
-	var df dataFile
-
+// path requested. If the generator fails, the temporary file is removed.
+func memoryMapAndGenerate(path string, header fileHeader, lock bool, generator func(buffer []uint32) error) (*dataFile, error) {
 	// Ensure the data folder exists
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return df, err
+		return nil, err
 	}
 
 	// Create a huge temporary empty file to fill with data
 	temp := path + "." + strconv.Itoa(rand.Int())
 	dump, err := os.Create(temp)
 	if err != nil {
-		return df, err
+		return nil, err
 	}
 
-	if err = ensureSize(dump, int64(len(dumpMagic))*4+int64(size)); err != nil {
+	if err = ensureSize(dump, headerWords*4+int64(header.size)); err != nil {
 		dump.Close()
 		os.Remove(temp)
-		return df, err
+		return nil, err
 	}
 
 	// Memory map the file for writing and fill it with the generator
@@ -141,24 +246,32 @@ func memoryMapAndGenerate(path string, size uint64, lock bool, generator func(bu
 	if err != nil {
 		dump.Close()
 		os.Remove(temp)
-		return df, err
+		return nil, err
+	}
+
+	data := buffer[headerWords:]
+	if err := generator(data); err != nil {
+		mem.Unmap()
+		dump.Close()
+		os.Remove(temp)
+		return nil, err
 	}
 
+	// the header is written last, so that partially written files are invalid
+	header.encode(uint32sAsBytes(buffer[:headerWords]), crc32.Checksum(uint32sAsBytes(data), castagnoli))
 	copy(buffer, dumpMagic)
-	data := buffer[len(dumpMagic):]
-	generator(data)
 
 	if err := mem.Unmap(); err != nil {
-		return df, err
+		return nil, err
 	}
 
 	if err := dump.Close(); err != nil {
-		return df, err
+		return nil, err
 	}
 
 	if err := os.Rename(temp, path); err != nil {
-		return df, err
+		return nil, err
 	}
 
-	return memoryMap(path, lock)
+	return memoryMap(path, lock, header)
 }
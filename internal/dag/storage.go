@@ -0,0 +1,189 @@
+package dag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Entry identifies the data of a cache, L1 cache or full dataset kept by a
+// Storage.
+type Entry struct {
+	Kind       FileKind
+	Name       string
+	Revision   int
+	Epoch      uint64
+	Seed       []byte // Seed of the epoch
+	Size       uint64 // Size of the data in bytes
+	LockMemory bool   // Whether to lock the memory in RAM
+}
+
+// FileName returns the name of the file storing the entry,
+// <kind>-<Name>-R<Revision>-<seed>.
+func (e Entry) FileName() string {
+	return fmt.Sprintf("%s-%s-R%d-%x", e.Kind, e.Name, e.Revision, e.Seed[:8])
+}
+
+func (e Entry) header() fileHeader {
+	return fileHeader{name: e.Name, epoch: e.Epoch, seed: e.Seed, size: e.Size}
+}
+
+// Buffer is the memory holding the data of an entry.
+type Buffer interface {
+	Data() []uint32
+	Path() string   // File backing the data, empty if the data is not stored
+	Release() error // Frees or unmaps the memory, the data must not be used afterwards
+}
+
+// Storage provides the memory of the caches, L1 caches and full datasets of
+// a DAG and optionally keeps them for later use.
+type Storage interface {
+	// Load returns the stored data of the entry. The error matches
+	// os.ErrNotExist if it is not stored and powerr.ErrCorruptedCache if it
+	// fails validation.
+	Load(entry Entry) (Buffer, error)
+
+	// Store allocates the data of the entry, fills it with generate and keeps
+	// it for later loads if the storage is persistent. Storages shared by
+	// processes may return the data another process stored in the meantime
+	// without calling generate.
+	Store(ctx context.Context, entry Entry, generate func(data []uint32) error) (Buffer, error)
+
+	// Remove deletes the stored data of an entry that is no longer needed,
+	// unless it is in use.
+	Remove(entry Entry) error
+}
+
+// storageDir is implemented by the storages backed by a directory, whose
+// files are managed by StoredFiles, VerifyStoredCache and PruneStorage.
+type storageDir interface {
+	Dir() string
+}
+
+type heapBuffer []uint32
+
+func (b heapBuffer) Data() []uint32 { return b }
+func (b heapBuffer) Path() string   { return "" }
+func (b heapBuffer) Release() error { return nil }
+
+type heapStorage struct{}
+
+// NewHeapStorage returns a storage keeping the data in slices on the Go heap,
+// generating it on every use.
+func NewHeapStorage() Storage {
+	return heapStorage{}
+}
+
+func (heapStorage) Load(entry Entry) (Buffer, error) {
+	return nil, os.ErrNotExist
+}
+
+func (heapStorage) Store(ctx context.Context, entry Entry, generate func([]uint32) error) (Buffer, error) {
+	data := make([]uint32, entry.Size/4)
+	if err := generate(data); err != nil {
+		return nil, err
+	}
+
+	return heapBuffer(data), nil
+}
+
+func (heapStorage) Remove(entry Entry) error {
+	return nil
+}
+
+type fileStorage struct {
+	dir string
+}
+
+// NewFileStorage returns a storage memory mapping files in a directory, which
+// processes sharing the directory coordinate with file locks: one process
+// generates a missing file while the others wait for it, and files mapped by
+// a process are not removed.
+func NewFileStorage(dir string) Storage {
+	return fileStorage{dir: dir}
+}
+
+func (s fileStorage) Dir() string {
+	return s.dir
+}
+
+func (s fileStorage) Load(entry Entry) (Buffer, error) {
+	df, err := memoryMap(filepath.Join(s.dir, entry.FileName()), entry.LockMemory, entry.header())
+	if err != nil {
+		return nil, err
+	}
+
+	return df, nil
+}
+
+func (s fileStorage) Store(ctx context.Context, entry Entry, generate func([]uint32) error) (Buffer, error) {
+	path := filepath.Join(s.dir, entry.FileName())
+
+	// Hold the generation lock, so that processes sharing the directory wait
+	// for the one generating the file and map it
+	unlock, err := lockGeneration(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if df, err := memoryMap(path, entry.LockMemory, entry.header()); err == nil {
+		return df, nil
+	}
+
+	df, err := memoryMapAndGenerate(path, entry.header(), entry.LockMemory, generate)
+	if err != nil {
+		return nil, err
+	}
+
+	return df, nil
+}
+
+func (s fileStorage) Remove(entry Entry) error {
+	_, err := removeFile(filepath.Join(s.dir, entry.FileName()))
+
+	return err
+}
+
+type readOnlyStorage struct {
+	dir      string
+	fallback Storage
+}
+
+// NewReadOnlyStorage returns a storage mapping the files pre-generated in a
+// directory, such as a read-only mount, without ever writing to it. Missing
+// entries are stored in the fallback storage, the heap if nil.
+func NewReadOnlyStorage(dir string, fallback Storage) Storage {
+	if fallback == nil {
+		fallback = NewHeapStorage()
+	}
+
+	return readOnlyStorage{dir: dir, fallback: fallback}
+}
+
+func (s readOnlyStorage) Dir() string {
+	return s.dir
+}
+
+func (s readOnlyStorage) Load(entry Entry) (Buffer, error) {
+	df, err := memoryMap(filepath.Join(s.dir, entry.FileName()), entry.LockMemory, entry.header())
+	if err != nil {
+		// entries generated since startup may be kept by the fallback
+		if buf, fallbackErr := s.fallback.Load(entry); fallbackErr == nil {
+			return buf, nil
+		}
+
+		return nil, err
+	}
+
+	return df, nil
+}
+
+func (s readOnlyStorage) Store(ctx context.Context, entry Entry, generate func([]uint32) error) (Buffer, error) {
+	return s.fallback.Store(ctx, entry, generate)
+}
+
+func (s readOnlyStorage) Remove(entry Entry) error {
+	return s.fallback.Remove(entry)
+}
@@ -47,64 +47,34 @@ func initMix(seed uint64, numLanes, numRegs int) [][]uint32 {
 	return mix
 }
 
-func round(cfg *Config, seed uint64, r uint32, mix [][]uint32, datasetSize uint64, lookup dag.LookupFunc, l1 []uint32) [][]uint32 {
-	state := initMixRngState(seed, uint32(cfg.RegisterCount))
+// round runs the program on the mix for round r.
+func (p *program) round(cfg *Config, r uint32, mix [][]uint32, datasetSize uint64, lookup dag.LookupFunc, l1 []uint32) [][]uint32 {
 	numItems := uint32(datasetSize / (2 * 128))
 	itemIndex := mix[r%uint32(cfg.LaneCount)][0] % numItems
 
 	item := lookup(itemIndex)
 
-	numWordsPerLane := len(item) / cfg.LaneCount
-	maxOperations := max(cfg.RoundCacheAccesses, cfg.RoundMathOperations)
-	for i := 0; i < maxOperations; i++ {
-		if i < cfg.RoundCacheAccesses {
-			src := state.nextSrc()
-			dst := state.nextDst()
-			sel := state.nextRng()
-
+	for _, op := range p.ops {
+		if op.cache {
 			for l := 0; l < cfg.LaneCount; l++ {
-				offset := mix[l][src] % (cfg.CacheBytes / 4)
-				mix[l][dst] = randomMerge(mix[l][dst], l1[offset], sel)
-			}
-		}
-
-		if i < cfg.RoundMathOperations {
-			srcRand := state.nextRng() % (uint32(cfg.RegisterCount) * uint32(cfg.RegisterCount-1))
-			src1 := srcRand % uint32(cfg.RegisterCount)
-			src2 := srcRand / uint32(cfg.RegisterCount)
-			if src2 >= src1 {
-				src2 += 1
+				offset := mix[l][op.src1] % (cfg.CacheBytes / 4)
+				mix[l][op.dst] = randomMerge(mix[l][op.dst], l1[offset], op.merge)
 			}
-
-			sel1 := state.nextRng()
-			dst := state.nextDst()
-			sel2 := state.nextRng()
-
+		} else {
 			for l := 0; l < cfg.LaneCount; l++ {
-				data := randomMath(mix[l][src1], mix[l][src2], sel1)
-				mix[l][dst] = randomMerge(mix[l][dst], data, sel2)
+				data := randomMath(mix[l][op.src1], mix[l][op.src2], op.math)
+				mix[l][op.dst] = randomMerge(mix[l][op.dst], data, op.merge)
 			}
 		}
 	}
 
 	// DAG access pattern.
-	dsts := make([]uint32, numWordsPerLane)
-	sels := make([]uint32, numWordsPerLane)
-	for i := 0; i < numWordsPerLane; i++ {
-		if i == 0 {
-			dsts[i] = 0
-		} else {
-			dsts[i] = state.nextDst()
-		}
-
-		sels[i] = state.nextRng()
-	}
-
+	numWordsPerLane := len(p.dagDsts)
 	for l := 0; l < cfg.LaneCount; l++ {
 		offset := ((uint32(l) ^ r) % uint32(cfg.LaneCount)) * uint32(numWordsPerLane)
 		for i := 0; i < numWordsPerLane; i++ {
 			word := item[offset+uint32(i)]
-			mix[l][dsts[i]] = randomMerge(mix[l][dsts[i]], word, sels[i])
+			mix[l][p.dagDsts[i]] = randomMerge(mix[l][p.dagDsts[i]], word, p.dagSels[i])
 		}
 	}
 
@@ -114,9 +84,10 @@ func round(cfg *Config, seed uint64, r uint32, mix [][]uint32, datasetSize uint6
 func Hash(cfg *Config, height, seed, datasetSize uint64, lookup dag.LookupFunc, l1 []uint32) []byte {
 	mix := initMix(seed, cfg.LaneCount, cfg.RegisterCount)
 
-	number := height / cfg.PeriodLength
+	// the program only depends on the period, so it is compiled once
+	prog := loadProgram(cfg, height/cfg.PeriodLength)
 	for i := 0; i < cfg.RoundCount; i++ {
-		mix = round(cfg, number, uint32(i), mix, datasetSize, lookup, l1)
+		mix = prog.round(cfg, uint32(i), mix, datasetSize, lookup, l1)
 	}
 
 	laneHash := make([]uint32, cfg.LaneCount)
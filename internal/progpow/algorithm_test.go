@@ -93,7 +93,7 @@ func TestInitMix(t *testing.T) {
 func TestRound(t *testing.T) {
 	tests := []struct {
 		cfg         *Config
-		seed        uint64
+		period      uint64
 		r           uint32
 		mix         [][]uint32
 		datasetSize uint64
@@ -103,9 +103,9 @@ func TestRound(t *testing.T) {
 	}{
 		{
 			// taken from final progpow094 test vector (height 30000, nonce 0x0000000000000001)
-			cfg:  progpow094Cfg,
-			seed: 3000,
-			r:    1,
+			cfg:    progpow094Cfg,
+			period: 3000,
+			r:      1,
 			mix: [][]uint32{
 				[]uint32{
 					0xb84870e0, 0xef34899d, 0x280a8fbf, 0xe7a5547d, 0xf77a9851, 0xe000a194, 0x57bb9ce0, 0xa74038fa,
@@ -833,7 +833,7 @@ func TestRound(t *testing.T) {
 	}
 
 	for i, tt := range tests[1:] {
-		result := round(tt.cfg, tt.seed, tt.r, tt.mix, tt.datasetSize, tt.lookup, tt.l1)
+		result := loadProgram(tt.cfg, tt.period).round(tt.cfg, tt.r, tt.mix, tt.datasetSize, tt.lookup, tt.l1)
 
 		if len(result) != len(tt.result) {
 			t.Errorf("failed on %d: length mismatch: have %d, want %d", i, len(result), len(tt.result))
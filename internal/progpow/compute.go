@@ -0,0 +1,32 @@
+package progpow
+
+// Compute092 computes the mix hash and digest of vanilla ProgPoW 0.9.2.
+func Compute092(hash []byte, height, nonce, datasetSize uint64, lookup func(index uint32) []uint32, l1 []uint32) ([]byte, []byte) {
+	return compute092(progpow092Cfg, hash, height, nonce, datasetSize, lookup, l1)
+}
+
+// Compute093 computes the mix hash and digest of vanilla ProgPoW 0.9.3, which
+// only changes the period length and the number of operations per round of 0.9.2.
+func Compute093(hash []byte, height, nonce, datasetSize uint64, lookup func(index uint32) []uint32, l1 []uint32) ([]byte, []byte) {
+	return compute092(progpow093Cfg, hash, height, nonce, datasetSize, lookup, l1)
+}
+
+// Compute094 computes the mix hash and digest of vanilla ProgPoW 0.9.4.
+func Compute094(hash []byte, height, nonce, datasetSize uint64, lookup func(index uint32) []uint32, l1 []uint32) ([]byte, []byte) {
+	return compute(hash, height, nonce, datasetSize, lookup, l1)
+}
+
+// Kernel092 generates the kernel of the period of height for ProgPoW 0.9.2.
+func Kernel092(height uint64, lang KernelLanguage) (string, error) {
+	return GenerateKernel(progpow092Cfg, height/progpow092Cfg.PeriodLength, lang)
+}
+
+// Kernel093 generates the kernel of the period of height for ProgPoW 0.9.3.
+func Kernel093(height uint64, lang KernelLanguage) (string, error) {
+	return GenerateKernel(progpow093Cfg, height/progpow093Cfg.PeriodLength, lang)
+}
+
+// Kernel094 generates the kernel of the period of height for ProgPoW 0.9.4.
+func Kernel094(height uint64, lang KernelLanguage) (string, error) {
+	return GenerateKernel(progpow094Cfg, height/progpow094Cfg.PeriodLength, lang)
+}
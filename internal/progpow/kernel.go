@@ -0,0 +1,216 @@
+package progpow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// KernelLanguage is the language of a kernel written by GenerateKernel.
+type KernelLanguage int
+
+const (
+	KernelCUDA KernelLanguage = iota
+	KernelOpenCL
+)
+
+func (l KernelLanguage) String() string {
+	switch l {
+	case KernelCUDA:
+		return "cuda"
+	case KernelOpenCL:
+		return "opencl"
+	default:
+		return fmt.Sprintf("KernelLanguage(%d)", int(l))
+	}
+}
+
+// GenerateKernel writes the progPowLoop function of a period, the part of the
+// kernel which changes every period, like the reference implementation
+// (ProgPow::getKern). It runs a round of the compiled program for the lane of
+// the thread and is included by the static part of the miner's kernel, whose
+// host defines PROGPOW_DAG_ELEMENTS as the number of items of the dataset.
+func GenerateKernel(cfg *Config, period uint64, lang KernelLanguage) (string, error) {
+	if lang != KernelCUDA && lang != KernelOpenCL {
+		return "", &powerr.VariantError{Kind: "kernel language", Name: lang.String()}
+	}
+
+	p := loadProgram(cfg, period)
+
+	var b strings.Builder
+	if lang == KernelCUDA {
+		b.WriteString("typedef unsigned int       uint32_t;\n")
+		b.WriteString("typedef unsigned long long uint64_t;\n")
+		b.WriteString("#if __CUDA_ARCH__ < 350\n")
+		b.WriteString("#define ROTL32(x,n) (((x) << ((n) & 31)) | ((x) >> ((32 - (n)) & 31)))\n")
+		b.WriteString("#define ROTR32(x,n) (((x) >> ((n) & 31)) | ((x) << ((32 - (n)) & 31)))\n")
+		b.WriteString("#else\n")
+		b.WriteString("#define ROTL32(x,n) __funnelshift_l((x), (x), (n))\n")
+		b.WriteString("#define ROTR32(x,n) __funnelshift_r((x), (x), (n))\n")
+		b.WriteString("#endif\n")
+		b.WriteString("#define min(a,b) ((a<b) ? a : b)\n")
+		b.WriteString("#define mul_hi(a, b) __umulhi(a, b)\n")
+		b.WriteString("#define clz(a) __clz(a)\n")
+		b.WriteString("#define popcount(a) __popc(a)\n\n")
+		b.WriteString("#define DEV_INLINE __device__ __forceinline__\n")
+		b.WriteString("#if (__CUDACC_VER_MAJOR__ > 8)\n")
+		b.WriteString("#define SHFL(x, y, z) __shfl_sync(0xFFFFFFFF, (x), (y), (z))\n")
+		b.WriteString("#else\n")
+		b.WriteString("#define SHFL(x, y, z) __shfl((x), (y), (z))\n")
+		b.WriteString("#endif\n\n")
+	} else {
+		b.WriteString("#ifndef GROUP_SIZE\n")
+		b.WriteString("#define GROUP_SIZE 128\n")
+		b.WriteString("#endif\n")
+		fmt.Fprintf(&b, "#define GROUP_SHARE (GROUP_SIZE / %d)\n\n", cfg.LaneCount)
+		b.WriteString("typedef unsigned int       uint32_t;\n")
+		b.WriteString("typedef unsigned long      uint64_t;\n")
+		b.WriteString("#define ROTL32(x, n) rotate((x), (uint32_t)(n))\n")
+		b.WriteString("#define ROTR32(x, n) rotate((x), (uint32_t)(32-n))\n\n")
+	}
+
+	fmt.Fprintf(&b, "#define PROGPOW_LANES           %d\n", cfg.LaneCount)
+	fmt.Fprintf(&b, "#define PROGPOW_REGS            %d\n", cfg.RegisterCount)
+	fmt.Fprintf(&b, "#define PROGPOW_DAG_LOADS       %d\n", cfg.DagLoads)
+	fmt.Fprintf(&b, "#define PROGPOW_CACHE_WORDS     %d\n", cfg.CacheBytes/4)
+	fmt.Fprintf(&b, "#define PROGPOW_CNT_DAG         %d\n", cfg.RoundCount)
+	fmt.Fprintf(&b, "#define PROGPOW_CNT_CACHE       %d\n", cfg.RoundCacheAccesses)
+	fmt.Fprintf(&b, "#define PROGPOW_CNT_MATH        %d\n\n", cfg.RoundMathOperations)
+
+	if lang == KernelCUDA {
+		b.WriteString("typedef struct __align__(16) {uint32_t s[PROGPOW_DAG_LOADS];} dag_t;\n\n")
+		fmt.Fprintf(&b, "// Inner loop for prog_seed %d\n", period)
+		b.WriteString("__device__ __forceinline__ void progPowLoop(const uint32_t loop,\n")
+		b.WriteString("        uint32_t mix[PROGPOW_REGS],\n")
+		b.WriteString("        const dag_t *g_dag,\n")
+		b.WriteString("        const uint32_t c_dag[PROGPOW_CACHE_WORDS],\n")
+		b.WriteString("        const bool hack_false)\n")
+	} else {
+		b.WriteString("typedef struct __attribute__ ((aligned (16))) {uint32_t s[PROGPOW_DAG_LOADS];} dag_t;\n\n")
+		fmt.Fprintf(&b, "// Inner loop for prog_seed %d\n", period)
+		b.WriteString("inline void progPowLoop(const uint32_t loop,\n")
+		b.WriteString("        volatile uint32_t mix_arg[PROGPOW_REGS],\n")
+		b.WriteString("        __global const dag_t *g_dag,\n")
+		b.WriteString("        __local const uint32_t c_dag[PROGPOW_CACHE_WORDS],\n")
+		b.WriteString("        __local uint64_t share[GROUP_SHARE],\n")
+		b.WriteString("        const bool hack_false)\n")
+	}
+
+	b.WriteString("{\n")
+	b.WriteString("dag_t data_dag;\n")
+	b.WriteString("uint32_t offset, data;\n")
+
+	// Work around the AMD OpenCL compiler miscompiling volatile arguments
+	if lang == KernelOpenCL {
+		b.WriteString("uint32_t mix[PROGPOW_REGS];\n")
+		b.WriteString("for(int i=0; i<PROGPOW_REGS; i++)\n")
+		b.WriteString("    mix[i] = mix_arg[i];\n")
+	}
+
+	if lang == KernelCUDA {
+		b.WriteString("const uint32_t lane_id = threadIdx.x & (PROGPOW_LANES-1);\n")
+	} else {
+		b.WriteString("const uint32_t lane_id = get_local_id(0) & (PROGPOW_LANES-1);\n")
+		b.WriteString("const uint32_t group_id = get_local_id(0) / PROGPOW_LANES;\n")
+	}
+
+	// The DAG item is loaded first and merged last to hide the latency of
+	// the load, its address depends on mix[0] of the lane of the round.
+	b.WriteString("// global load to sequential locations\n")
+	if lang == KernelCUDA {
+		b.WriteString("offset = SHFL(mix[0], loop%PROGPOW_LANES, PROGPOW_LANES);\n")
+	} else {
+		b.WriteString("if(lane_id == (loop % PROGPOW_LANES))\n")
+		b.WriteString("    share[group_id] = mix[0];\n")
+		b.WriteString("barrier(CLK_LOCAL_MEM_FENCE);\n")
+		b.WriteString("offset = share[group_id];\n")
+	}
+	b.WriteString("offset %= PROGPOW_DAG_ELEMENTS;\n")
+	b.WriteString("offset = offset * PROGPOW_LANES + (lane_id ^ loop) % PROGPOW_LANES;\n")
+	b.WriteString("data_dag = g_dag[offset];\n")
+	b.WriteString("// hack to prevent compiler from reordering LD and usage\n")
+	if lang == KernelCUDA {
+		b.WriteString("if (hack_false) __threadfence_block();\n")
+	} else {
+		b.WriteString("if (hack_false) barrier(CLK_LOCAL_MEM_FENCE);\n")
+	}
+
+	var cacheCount, mathCount int
+	for _, op := range p.ops {
+		dst := fmt.Sprintf("mix[%d]", op.dst)
+		if op.cache {
+			fmt.Fprintf(&b, "// cache load %d\n", cacheCount)
+			fmt.Fprintf(&b, "offset = mix[%d] %% PROGPOW_CACHE_WORDS;\n", op.src1)
+			b.WriteString("data = c_dag[offset];\n")
+			b.WriteString(kernelMerge(dst, "data", op.merge))
+			cacheCount++
+		} else {
+			fmt.Fprintf(&b, "// random math %d\n", mathCount)
+			b.WriteString(kernelMath("data", fmt.Sprintf("mix[%d]", op.src1), fmt.Sprintf("mix[%d]", op.src2), op.math))
+			b.WriteString(kernelMerge(dst, "data", op.merge))
+			mathCount++
+		}
+	}
+
+	for i := range p.dagDsts {
+		dst := fmt.Sprintf("mix[%d]", p.dagDsts[i])
+		b.WriteString(kernelMerge(dst, fmt.Sprintf("data_dag.s[%d]", i), p.dagSels[i]))
+	}
+
+	if lang == KernelOpenCL {
+		b.WriteString("for(int i=0; i<PROGPOW_REGS; i++)\n")
+		b.WriteString("    mix_arg[i] = mix[i];\n")
+	}
+	b.WriteString("}\n\n")
+
+	return b.String(), nil
+}
+
+// kernelMerge is the source of randomMerge.
+func kernelMerge(a, b string, selector uint32) string {
+	x := ((selector >> 16) % 31) + 1
+
+	switch selector % 4 {
+	case 0:
+		return fmt.Sprintf("%s = (%s * 33) + %s;\n", a, a, b)
+	case 1:
+		return fmt.Sprintf("%s = (%s ^ %s) * 33;\n", a, a, b)
+	case 2:
+		return fmt.Sprintf("%s = ROTL32(%s, %d) ^ %s;\n", a, a, x, b)
+	case 3:
+		return fmt.Sprintf("%s = ROTR32(%s, %d) ^ %s;\n", a, a, x, b)
+	}
+
+	return "#error\n"
+}
+
+// kernelMath is the source of randomMath.
+func kernelMath(d, a, b string, selector uint32) string {
+	switch selector % 11 {
+	case 0:
+		return fmt.Sprintf("%s = %s + %s;\n", d, a, b)
+	case 1:
+		return fmt.Sprintf("%s = %s * %s;\n", d, a, b)
+	case 2:
+		return fmt.Sprintf("%s = mul_hi(%s, %s);\n", d, a, b)
+	case 3:
+		return fmt.Sprintf("%s = min(%s, %s);\n", d, a, b)
+	case 4:
+		return fmt.Sprintf("%s = ROTL32(%s, %s %% 32);\n", d, a, b)
+	case 5:
+		return fmt.Sprintf("%s = ROTR32(%s, %s %% 32);\n", d, a, b)
+	case 6:
+		return fmt.Sprintf("%s = %s & %s;\n", d, a, b)
+	case 7:
+		return fmt.Sprintf("%s = %s | %s;\n", d, a, b)
+	case 8:
+		return fmt.Sprintf("%s = %s ^ %s;\n", d, a, b)
+	case 9:
+		return fmt.Sprintf("%s = clz(%s) + clz(%s);\n", d, a, b)
+	case 10:
+		return fmt.Sprintf("%s = popcount(%s) + popcount(%s);\n", d, a, b)
+	}
+
+	return "#error\n"
+}
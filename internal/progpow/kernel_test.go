@@ -0,0 +1,499 @@
+package progpow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// kernelMachine interprets a progPowLoop written by GenerateKernel, running
+// every statement for the lanes of a single group in lockstep. The device
+// built-ins are modelled on the Go functions they are expected to match.
+type kernelMachine struct {
+	body    []string
+	defines map[string]uint32
+	dag     []uint32
+	l1      []uint32
+
+	loop    uint32
+	lane    int
+	mix     [][]uint32
+	vars    []map[string]uint32
+	dagData [][]uint32
+	share   map[uint32]uint32
+}
+
+func newKernelMachine(source string, dag, l1 []uint32, numItems uint32) (*kernelMachine, error) {
+	m := &kernelMachine{
+		defines: map[string]uint32{"PROGPOW_DAG_ELEMENTS": numItems},
+		dag:     dag,
+		l1:      l1,
+	}
+
+	inBody := false
+	for _, line := range strings.Split(source, "\n") {
+		switch {
+		case line == "{":
+			inBody = true
+		case line == "}":
+			inBody = false
+		case inBody:
+			m.body = append(m.body, strings.TrimSpace(line))
+		case strings.HasPrefix(line, "#define "):
+			fields := strings.Fields(line)
+			if value, err := strconv.ParseUint(fields[len(fields)-1], 0, 32); err == nil && len(fields) == 3 {
+				m.defines[fields[1]] = uint32(value)
+			}
+		}
+	}
+
+	if len(m.body) == 0 {
+		return nil, errors.New("progPowLoop not found")
+	}
+
+	return m, nil
+}
+
+// run executes the loop for round r on the mix of every lane.
+func (m *kernelMachine) run(r uint32, mix [][]uint32) error {
+	m.loop = r
+	m.mix = mix
+	m.share = make(map[uint32]uint32)
+	m.vars = make([]map[string]uint32, len(mix))
+	m.dagData = make([][]uint32, len(mix))
+	for l := range mix {
+		m.vars[l] = make(map[string]uint32)
+	}
+
+	for i := 0; i < len(m.body); i++ {
+		line := m.body[i]
+
+		var cond, stmt string
+		switch {
+		case line == "" || strings.HasPrefix(line, "//"):
+			continue
+		case strings.HasPrefix(line, "for("):
+			// copies between mix_arg and mix, which are the same registers here
+			i++
+			continue
+		case strings.HasPrefix(line, "dag_t ") || strings.HasPrefix(line, "uint32_t "):
+			continue
+		case strings.HasPrefix(line, "if"):
+			end := matchParen(line, strings.Index(line, "("))
+			cond, stmt = line[strings.Index(line, "(")+1:end], strings.TrimSpace(line[end+1:])
+			if stmt == "" {
+				i++
+				stmt = m.body[i]
+			}
+		default:
+			stmt = strings.TrimPrefix(line, "const uint32_t ")
+		}
+
+		for m.lane = range mix {
+			if cond != "" {
+				if value, err := m.eval(cond); err != nil {
+					return err
+				} else if value == 0 {
+					continue
+				}
+			}
+
+			if err := m.exec(stmt); err != nil {
+				return fmt.Errorf("%s: %v", stmt, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(s)
+}
+
+// exec executes an assignment or a synchronization, which is a no-op as the
+// lanes run in lockstep.
+func (m *kernelMachine) exec(stmt string) error {
+	stmt = strings.TrimSuffix(stmt, ";")
+	switch stmt {
+	case "barrier(CLK_LOCAL_MEM_FENCE)", "__threadfence_block()":
+		return nil
+	}
+
+	op := "="
+	index := strings.Index(stmt, "=")
+	if index < 0 {
+		return errors.New("not an assignment")
+	} else if index > 0 && stmt[index-1] == '%' {
+		op = "%="
+		index--
+	}
+
+	lhs := strings.TrimSpace(stmt[:index])
+	rhs := strings.TrimSpace(stmt[index+len(op):])
+
+	if lhs == "data_dag" {
+		if !strings.HasPrefix(rhs, "g_dag[") {
+			return errors.New("data_dag not loaded from g_dag")
+		}
+
+		element, err := m.eval(rhs[len("g_dag[") : len(rhs)-1])
+		if err != nil {
+			return err
+		}
+
+		loads := m.defines["PROGPOW_DAG_LOADS"]
+		m.dagData[m.lane] = m.dag[element*loads : (element+1)*loads]
+
+		return nil
+	}
+
+	value, err := m.eval(rhs)
+	if err != nil {
+		return err
+	}
+
+	if op == "%=" {
+		current, err := m.eval(lhs)
+		if err != nil {
+			return err
+		}
+		value = current % value
+	}
+
+	if open := strings.Index(lhs, "["); open >= 0 {
+		element, err := m.eval(lhs[open+1 : len(lhs)-1])
+		if err != nil {
+			return err
+		}
+
+		switch lhs[:open] {
+		case "mix":
+			m.mix[m.lane][element] = value
+		case "share":
+			m.share[element] = value
+		default:
+			return fmt.Errorf("assignment to %s", lhs)
+		}
+
+		return nil
+	}
+
+	m.vars[m.lane][lhs] = value
+
+	return nil
+}
+
+func (m *kernelMachine) eval(expr string) (uint32, error) {
+	p := &exprParser{m: m, tokens: tokenize(expr)}
+	value := p.binary(0)
+	if p.err == nil && p.pos != len(p.tokens) {
+		p.err = fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+
+	return value, p.err
+}
+
+func tokenize(expr string) []string {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ':
+			i++
+		case c == '=' && i+1 < len(expr) && expr[i+1] == '=':
+			tokens = append(tokens, "==")
+			i += 2
+		case c == '_' || c == '.' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9'):
+			j := i
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '.' || ('a' <= expr[j] && expr[j] <= 'z') ||
+				('A' <= expr[j] && expr[j] <= 'Z') || ('0' <= expr[j] && expr[j] <= '9')) {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// exprParser evaluates the C expressions of the kernel with the usual
+// precedence of the binary operators.
+type exprParser struct {
+	m      *kernelMachine
+	tokens []string
+	pos    int
+	err    error
+}
+
+var precedence = map[string]int{
+	"|":  1,
+	"^":  2,
+	"&":  3,
+	"==": 4,
+	"<":  5,
+	"+":  6,
+	"-":  6,
+	"*":  7,
+	"/":  7,
+	"%":  7,
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *exprParser) expect(token string) {
+	if p.peek() != token && p.err == nil {
+		p.err = fmt.Errorf("expected %q, found %q", token, p.peek())
+	}
+	p.pos++
+}
+
+func (p *exprParser) binary(minPrecedence int) uint32 {
+	left := p.primary()
+	for p.err == nil {
+		op := p.peek()
+		prec, ok := precedence[op]
+		if !ok || prec <= minPrecedence {
+			return left
+		}
+		p.pos++
+
+		right := p.binary(prec)
+		switch op {
+		case "|":
+			left |= right
+		case "^":
+			left ^= right
+		case "&":
+			left &= right
+		case "==", "<":
+			if (op == "==" && left == right) || (op == "<" && left < right) {
+				left = 1
+			} else {
+				left = 0
+			}
+		case "+":
+			left += right
+		case "-":
+			left -= right
+		case "*":
+			left *= right
+		case "/", "%":
+			if right == 0 {
+				p.err = errors.New("division by zero")
+			} else if op == "/" {
+				left /= right
+			} else {
+				left %= right
+			}
+		}
+	}
+
+	return left
+}
+
+func (p *exprParser) args() []uint32 {
+	var args []uint32
+	p.expect("(")
+	for p.err == nil && p.peek() != ")" {
+		if len(args) > 0 {
+			p.expect(",")
+		}
+		args = append(args, p.binary(0))
+	}
+	p.expect(")")
+
+	return args
+}
+
+func (p *exprParser) primary() uint32 {
+	token := p.peek()
+	p.pos++
+
+	if token == "(" {
+		value := p.binary(0)
+		p.expect(")")
+		return value
+	} else if value, err := strconv.ParseUint(token, 0, 32); err == nil {
+		return uint32(value)
+	}
+
+	m := p.m
+	switch token {
+	case "SHFL":
+		// the value is read from the mix of the source lane
+		start := p.pos
+		args := p.args()
+		if p.err != nil || len(args) != 3 {
+			break
+		}
+
+		end, lane := p.pos, m.lane
+		m.lane = int(args[1] % args[2])
+		p.pos = start
+		value := p.args()[0]
+		m.lane, p.pos = lane, end
+
+		return value
+	case "ROTL32", "ROTR32", "mul_hi", "min", "clz", "popcount", "get_local_id":
+		args := p.args()
+		if p.err != nil {
+			break
+		}
+
+		switch token {
+		case "ROTL32":
+			return rotl32(args[0], args[1])
+		case "ROTR32":
+			return rotr32(args[0], args[1])
+		case "mul_hi":
+			return mul_hi32(args[0], args[1])
+		case "min":
+			return minUint32(args[0], args[1])
+		case "clz":
+			return clz32(args[0])
+		case "popcount":
+			return popcount32(args[0])
+		case "get_local_id":
+			return uint32(m.lane)
+		}
+	case "mix", "data_dag.s", "c_dag", "share":
+		p.expect("[")
+		index := p.binary(0)
+		p.expect("]")
+		if p.err != nil {
+			break
+		}
+
+		switch token {
+		case "mix":
+			return m.mix[m.lane][index]
+		case "data_dag.s":
+			return m.dagData[m.lane][index]
+		case "c_dag":
+			return m.l1[index]
+		case "share":
+			return m.share[index]
+		}
+	case "threadIdx.x":
+		return uint32(m.lane)
+	case "loop":
+		return m.loop
+	case "hack_false":
+		return 0
+	default:
+		if value, ok := m.defines[token]; ok {
+			return value
+		} else if value, ok := m.vars[m.lane][token]; ok {
+			return value
+		}
+
+		if p.err == nil {
+			p.err = fmt.Errorf("unknown identifier %q", token)
+		}
+	}
+
+	return 0
+}
+
+func TestGenerateKernel(t *testing.T) {
+	tests := []struct {
+		cfg    *Config
+		period uint64
+	}{
+		{progpow092Cfg, 30000 / 50},
+		{progpow093Cfg, 0},
+		{progpow094Cfg, 1234567 / 10},
+		// meowpow, with fewer operations per round
+		{&Config{
+			PeriodLength:        6,
+			DagLoads:            4,
+			CacheBytes:          16 * 1024,
+			LaneCount:           16,
+			RegisterCount:       32,
+			RoundCount:          64,
+			RoundCacheAccesses:  6,
+			RoundMathOperations: 9,
+		}, 1234567 / 6},
+	}
+
+	const numItems = 64
+
+	rng := newKiss99(1, 2, 3, 4)
+	dag := make([]uint32, numItems*2*128/4)
+	for i := range dag {
+		dag[i] = rng.next()
+	}
+
+	lookup := func(index uint32) []uint32 {
+		return dag[index*64 : (index+1)*64]
+	}
+
+	for i, tt := range tests {
+		l1 := make([]uint32, tt.cfg.CacheBytes/4)
+		for j := range l1 {
+			l1[j] = rng.next()
+		}
+
+		for _, lang := range []KernelLanguage{KernelCUDA, KernelOpenCL} {
+			source, err := GenerateKernel(tt.cfg, tt.period, lang)
+			if err != nil {
+				t.Errorf("failed on %d: %s: %v", i, lang, err)
+				continue
+			}
+
+			m, err := newKernelMachine(source, dag, l1, numItems)
+			if err != nil {
+				t.Errorf("failed on %d: %s: %v", i, lang, err)
+				continue
+			}
+
+			mix := initMix(tt.period, tt.cfg.LaneCount, tt.cfg.RegisterCount)
+			kernelMix := initMix(tt.period, tt.cfg.LaneCount, tt.cfg.RegisterCount)
+			prog := loadProgram(tt.cfg, tt.period)
+			for r := 0; r < tt.cfg.RoundCount; r++ {
+				mix = prog.round(tt.cfg, uint32(r), mix, numItems*2*128, lookup, l1)
+				if err := m.run(uint32(r), kernelMix); err != nil {
+					t.Errorf("failed on %d: %s: round %d: %v", i, lang, r, err)
+					break
+				}
+
+				if fmt.Sprint(mix) != fmt.Sprint(kernelMix) {
+					t.Errorf("failed on %d: %s: round %d: mix mismatch", i, lang, r)
+					break
+				}
+			}
+		}
+	}
+
+	if _, err := GenerateKernel(progpow094Cfg, 0, KernelLanguage(2)); !errors.Is(err, powerr.ErrUnsupportedVariant) {
+		t.Errorf("unknown kernel language: have %v, want %v", err, powerr.ErrUnsupportedVariant)
+	}
+}
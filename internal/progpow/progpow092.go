@@ -0,0 +1,72 @@
+// ethash: C/C++ implementation of Ethash, the Ethereum Proof of Work algorithm.
+// Copyright 2018-2019 Pawel Bylica.
+// Licensed under the Apache License, Version 2.0.
+
+package progpow
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/sencha-dev/powkit/internal/common/convutil"
+	"github.com/sencha-dev/powkit/internal/crypto"
+)
+
+var progpow092Cfg = &Config{
+	PeriodLength:        50,
+	DagLoads:            4,
+	CacheBytes:          16 * 1024,
+	LaneCount:           16,
+	RegisterCount:       32,
+	RoundCount:          64,
+	RoundCacheAccesses:  12,
+	RoundMathOperations: 20,
+}
+
+var progpow093Cfg = &Config{
+	PeriodLength:        10,
+	DagLoads:            4,
+	CacheBytes:          16 * 1024,
+	LaneCount:           16,
+	RegisterCount:       32,
+	RoundCount:          64,
+	RoundCacheAccesses:  11,
+	RoundMathOperations: 18,
+}
+
+// keccakProgpow256 is the keccak_f800 hash used by ProgPoW 0.9.2 and 0.9.3
+// for both the seed and the final hash, which (unlike 0.9.4) both absorb
+// the header hash.
+func keccakProgpow256(hash []byte, seed uint64, mixHash []byte) []byte {
+	var state [25]uint32
+	for i := 0; i < 8; i++ {
+		state[i] = binary.LittleEndian.Uint32(hash[i*4 : i*4+4])
+	}
+
+	state[8] = uint32(seed)
+	state[9] = uint32(seed >> 32)
+
+	if mixHash != nil {
+		for i := 0; i < 8; i++ {
+			state[i+10] = binary.LittleEndian.Uint32(mixHash[i*4 : i*4+4])
+		}
+	}
+
+	crypto.KeccakF800(&state)
+
+	return convutil.Uint32ArrayToBytes(state[:8], binary.LittleEndian)
+}
+
+func keccakProgpow64(hash []byte, nonce uint64) uint64 {
+	seed := keccakProgpow256(hash, nonce, nil)
+
+	return bits.ReverseBytes64(binary.LittleEndian.Uint64(seed[:8]))
+}
+
+func compute092(cfg *Config, hash []byte, height, nonce, datasetSize uint64, lookup func(index uint32) []uint32, l1 []uint32) ([]byte, []byte) {
+	seed := keccakProgpow64(hash, nonce)
+	mixHash := Hash(cfg, height, seed, datasetSize, lookup, l1)
+	digest := keccakProgpow256(hash, seed, mixHash)
+
+	return mixHash, digest
+}
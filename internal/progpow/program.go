@@ -0,0 +1,145 @@
+package progpow
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// programCacheSize is the number of compiled programs kept, enough for the
+// few latest periods of every config in use.
+const programCacheSize = 16
+
+// operation is a step of the random program of a period: either merging a
+// word of the L1 cache addressed by register src1 into dst, or merging the
+// random math of registers src1 and src2 into dst.
+type operation struct {
+	cache bool
+	src1  uint32
+	src2  uint32
+	dst   uint32
+	math  uint32 // Selector of randomMath
+	merge uint32 // Selector of randomMerge
+}
+
+// program is the sequence of cache, math and DAG operations of a period,
+// which is identical for every round of every hash in the period.
+type program struct {
+	ops     []operation
+	dagDsts []uint32 // Registers the DAG words of a lane are merged into
+	dagSels []uint32 // Selectors of randomMerge for the DAG words
+}
+
+// compileProgram derives the program of a period from its KISS99 sequence, in
+// the order round draws it.
+func compileProgram(cfg *Config, period uint64) *program {
+	state := initMixRngState(period, uint32(cfg.RegisterCount))
+	numRegs := uint32(cfg.RegisterCount)
+
+	p := &program{
+		ops:     make([]operation, 0, cfg.RoundCacheAccesses+cfg.RoundMathOperations),
+		dagDsts: make([]uint32, cfg.DagLoads),
+		dagSels: make([]uint32, cfg.DagLoads),
+	}
+
+	maxOperations := max(cfg.RoundCacheAccesses, cfg.RoundMathOperations)
+	for i := 0; i < maxOperations; i++ {
+		if i < cfg.RoundCacheAccesses {
+			op := operation{cache: true}
+			op.src1 = state.nextSrc()
+			op.dst = state.nextDst()
+			op.merge = state.nextRng()
+			p.ops = append(p.ops, op)
+		}
+
+		if i < cfg.RoundMathOperations {
+			var op operation
+			srcRand := state.nextRng() % (numRegs * (numRegs - 1))
+			op.src1 = srcRand % numRegs
+			op.src2 = srcRand / numRegs
+			if op.src2 >= op.src1 {
+				op.src2 += 1
+			}
+
+			op.math = state.nextRng()
+			op.dst = state.nextDst()
+			op.merge = state.nextRng()
+			p.ops = append(p.ops, op)
+		}
+	}
+
+	// DAG access pattern.
+	for i := 0; i < cfg.DagLoads; i++ {
+		if i > 0 {
+			p.dagDsts[i] = state.nextDst()
+		}
+		p.dagSels[i] = state.nextRng()
+	}
+
+	return p
+}
+
+type programKey struct {
+	cfg    Config
+	period uint64
+}
+
+type cachedProgram struct {
+	used    uint64 // Compilation count when last loaded, accessed atomically
+	program *program
+}
+
+var programs struct {
+	compiled uint64     // Number of programs compiled, accessed atomically
+	cache    sync.Map   // Cached programs by programKey
+	mu       sync.Mutex // Serializes the compilation and eviction of programs
+	size     int        // Number of cached programs, protected by mu
+}
+
+// loadProgram returns the compiled program of the period, compiling it if it
+// is not cached. Cached programs are loaded without locking, so that parallel
+// hashes are not serialized. The least recently used program is evicted once
+// programCacheSize programs are cached, the programs used since the last
+// compilation counting as equally recent.
+func loadProgram(cfg *Config, period uint64) *program {
+	key := programKey{cfg: *cfg, period: period}
+
+	if value, ok := programs.cache.Load(key); ok {
+		cached := value.(*cachedProgram)
+		if compiled := atomic.LoadUint64(&programs.compiled); atomic.LoadUint64(&cached.used) != compiled {
+			atomic.StoreUint64(&cached.used, compiled)
+		}
+
+		return cached.program
+	}
+
+	programs.mu.Lock()
+	defer programs.mu.Unlock()
+
+	if value, ok := programs.cache.Load(key); ok {
+		return value.(*cachedProgram).program
+	}
+
+	if programs.size >= programCacheSize {
+		var evict interface{}
+		oldest := uint64(math.MaxUint64)
+		programs.cache.Range(func(key, value interface{}) bool {
+			if used := atomic.LoadUint64(&value.(*cachedProgram).used); used < oldest {
+				evict, oldest = key, used
+			}
+
+			return true
+		})
+		programs.cache.Delete(evict)
+		programs.size--
+	}
+
+	cached := &cachedProgram{
+		used:    atomic.AddUint64(&programs.compiled, 1),
+		program: compileProgram(cfg, period),
+	}
+	programs.cache.Store(key, cached)
+	programs.size++
+
+	return cached.program
+}
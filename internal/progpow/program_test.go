@@ -0,0 +1,79 @@
+package progpow
+
+import (
+	"testing"
+)
+
+func TestCompileProgram(t *testing.T) {
+	tests := []struct {
+		cfg    *Config
+		period uint64
+	}{
+		{progpow092Cfg, 30000 / 50},
+		{progpow093Cfg, 0},
+		{progpow094Cfg, 1234567},
+	}
+
+	for i, tt := range tests {
+		p := compileProgram(tt.cfg, tt.period)
+		if len(p.ops) != tt.cfg.RoundCacheAccesses+tt.cfg.RoundMathOperations {
+			t.Errorf("failed on %d: operation count mismatch: have %d, want %d", i, len(p.ops),
+				tt.cfg.RoundCacheAccesses+tt.cfg.RoundMathOperations)
+		} else if len(p.dagDsts) != tt.cfg.DagLoads || p.dagDsts[0] != 0 {
+			t.Errorf("failed on %d: dag destinations mismatch: have %v", i, p.dagDsts)
+		}
+
+		// the destinations follow the shuffled sequence of the period
+		state := initMixRngState(tt.period, uint32(tt.cfg.RegisterCount))
+		for j, op := range p.ops {
+			if dst := state.nextDst(); op.dst != dst {
+				t.Errorf("failed on %d: operation %d destination mismatch: have %d, want %d", i, j, op.dst, dst)
+			}
+
+			if !op.cache && op.src1 == op.src2 {
+				t.Errorf("failed on %d: operation %d has equal sources %d", i, j, op.src1)
+			}
+		}
+	}
+}
+
+func TestLoadProgram(t *testing.T) {
+	first := loadProgram(progpow094Cfg, 0)
+	if loadProgram(progpow094Cfg, 0) != first {
+		t.Errorf("program of the same period recompiled")
+	}
+
+	// configs are compared by value, firopow builds its config per hash
+	cfg := *progpow094Cfg
+	if loadProgram(&cfg, 0) != first {
+		t.Errorf("program of an equal config recompiled")
+	} else if loadProgram(progpow092Cfg, 0) == first {
+		t.Errorf("program of another config shared")
+	}
+
+	for period := uint64(1); period <= programCacheSize; period++ {
+		loadProgram(progpow094Cfg, period)
+	}
+
+	var size int
+	programs.cache.Range(func(_, _ interface{}) bool {
+		size++
+		return true
+	})
+
+	if size > programCacheSize {
+		t.Errorf("cache size mismatch: have %d, want at most %d", size, programCacheSize)
+	} else if loadProgram(progpow094Cfg, 0) == first {
+		t.Errorf("least recently used program not evicted")
+	}
+}
+
+func BenchmarkLoadProgram(b *testing.B) {
+	loadProgram(progpow094Cfg, 0)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			loadProgram(progpow094Cfg, 0)
+		}
+	})
+}
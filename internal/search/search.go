@@ -0,0 +1,115 @@
+// Package search implements the CPU nonce search shared by the clients of
+// every digest producing algorithm.
+package search
+
+import (
+	"context"
+	"math/big"
+	"runtime"
+	"sync"
+)
+
+// chunkSize is the number of consecutive nonces a worker claims at once,
+// which is also how often it checks for cancellation.
+const chunkSize = 1 << 8
+
+// Result is the nonce found by a search, along with its mix (nil for the
+// algorithms that do not produce one) and its digest.
+type Result struct {
+	Nonce  uint64
+	Mix    []byte
+	Digest []byte
+}
+
+// ComputeFunc computes the mix and digest of a single nonce.
+type ComputeFunc func(nonce uint64) ([]byte, []byte, error)
+
+// Search computes count nonces starting at startNonce across workers
+// goroutines (runtime.NumCPU() if workers is not positive) and returns the
+// lowest nonce whose big-endian digest is less than or equal to target, or
+// nil if none of them is. Since compute functions are generally not safe for
+// concurrent use, newCompute is called once per worker.
+func Search(ctx context.Context, startNonce, count uint64, workers int, target *big.Int, newCompute func() ComputeFunc) (*Result, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var (
+		mu     sync.Mutex
+		next   uint64 // offset of the next unclaimed chunk
+		result *Result
+		err    error
+	)
+
+	// claim returns the next chunk of nonces, which is empty once the range is
+	// exhausted, a nonce was found before it or the search failed.
+	claim := func() (uint64, uint64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		end := count
+		if result != nil {
+			end = result.Nonce - startNonce
+		}
+
+		if err != nil || next >= end {
+			return 0, 0
+		}
+
+		start := next
+		next += chunkSize
+		if next > end || next < start {
+			next = end
+		}
+
+		return start, next
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			compute := newCompute()
+			for {
+				start, end := claim()
+				if start == end {
+					return
+				} else if ctxErr := ctx.Err(); ctxErr != nil {
+					mu.Lock()
+					if err == nil {
+						err = ctxErr
+					}
+					mu.Unlock()
+					return
+				}
+
+				for offset := start; offset < end; offset++ {
+					nonce := startNonce + offset
+					mix, digest, computeErr := compute(nonce)
+					if computeErr != nil || new(big.Int).SetBytes(digest).Cmp(target) <= 0 {
+						mu.Lock()
+						if computeErr != nil {
+							if err == nil {
+								err = computeErr
+							}
+						} else if result == nil || offset < result.Nonce-startNonce {
+							result = &Result{Nonce: nonce, Mix: mix, Digest: digest}
+						}
+						mu.Unlock()
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// newModCompute returns a compute function whose digest is zero for every
+// nonce divisible by mod and the maximum value otherwise.
+func newModCompute(mod uint64) func() ComputeFunc {
+	return func() ComputeFunc {
+		return func(nonce uint64) ([]byte, []byte, error) {
+			digest := make([]byte, 32)
+			if nonce%mod != 0 {
+				for i := range digest {
+					digest[i] = 0xff
+				}
+			}
+
+			mix := make([]byte, 8)
+			binary.BigEndian.PutUint64(mix, nonce)
+
+			return mix, digest, nil
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		startNonce uint64
+		count      uint64
+		workers    int
+		mod        uint64
+		found      bool
+		nonce      uint64
+	}{
+		{
+			startNonce: 1,
+			count:      10000,
+			workers:    8,
+			mod:        4321,
+			found:      true,
+			nonce:      4321,
+		},
+		{
+			startNonce: 4322,
+			count:      10000,
+			workers:    3,
+			mod:        4321,
+			found:      true,
+			nonce:      8642,
+		},
+		{
+			startNonce: 1,
+			count:      4320,
+			workers:    0,
+			mod:        4321,
+			found:      false,
+		},
+		{
+			startNonce: 1<<64 - 10,
+			count:      20,
+			workers:    4,
+			mod:        1 << 63,
+			found:      true,
+			nonce:      0,
+		},
+	}
+
+	target := big.NewInt(1)
+	for i, tt := range tests {
+		result, err := Search(context.Background(), tt.startNonce, tt.count, tt.workers, target, newModCompute(tt.mod))
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if (result != nil) != tt.found {
+			t.Errorf("failed on %d: found mismatch: have %t, want %t", i, result != nil, tt.found)
+		} else if result != nil && result.Nonce != tt.nonce {
+			t.Errorf("failed on %d: nonce mismatch: have %d, want %d", i, result.Nonce, tt.nonce)
+		} else if result != nil && binary.BigEndian.Uint64(result.Mix) != tt.nonce {
+			t.Errorf("failed on %d: mix mismatch", i)
+		}
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Search(ctx, 0, 1<<20, 2, big.NewInt(0), newModCompute(1<<40))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, have %v", err)
+	}
+
+	computeErr := errors.New("compute failed")
+	newCompute := func() ComputeFunc {
+		return func(nonce uint64) ([]byte, []byte, error) {
+			return nil, nil, computeErr
+		}
+	}
+
+	_, err = Search(context.Background(), 0, 1<<20, 2, big.NewInt(0), newCompute)
+	if err != computeErr {
+		t.Errorf("expected compute error, have %v", err)
+	}
+}
@@ -3,20 +3,59 @@
 package kawpow
 
 import (
-	"fmt"
-	"runtime"
+	"context"
+	"math/big"
 
+	"github.com/sencha-dev/powkit/internal/batch"
 	"github.com/sencha-dev/powkit/internal/common"
 	"github.com/sencha-dev/powkit/internal/dag"
+	"github.com/sencha-dev/powkit/internal/progpow"
+	"github.com/sencha-dev/powkit/internal/search"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// SearchResult is the nonce found by Search, along with its mix and digest.
+type SearchResult = search.Result
+
+// ComputeJob is a single nonce to compute with ComputeBatch.
+type ComputeJob = batch.ComputeJob
+
+// ComputeResult is the outcome of a ComputeJob.
+type ComputeResult = batch.ComputeResult
+
+// StoredCache is a cache, L1 cache or dataset file in the storage directory.
+type StoredCache = dag.StoredFile
+
+// CacheEvent reports a step in the lifecycle of a cache, see SetEventHandler.
+type CacheEvent = dag.Event
+
+// Storage keeps the caches and datasets, see SetStorage.
+type Storage = dag.Storage
+
+// KernelLanguage is the language of the source written by Kernel.
+type KernelLanguage = progpow.KernelLanguage
+
+const (
+	KernelCUDA   = progpow.KernelCUDA
+	KernelOpenCL = progpow.KernelOpenCL
 )
 
 type Client struct {
-	data *dag.DAG
+	data    *dag.DAG
+	cfg     *progpow.Config
+	padding [15]uint32
+	workers int
 }
 
 func New(cfg dag.Config) *Client {
+	return newClient(cfg, ravencoinCfg, ravencoinKawpow)
+}
+
+func newClient(cfg dag.Config, progpowCfg *progpow.Config, padding [15]uint32) *Client {
 	client := &Client{
-		data: dag.New(cfg),
+		data:    dag.New(cfg),
+		cfg:     progpowCfg,
+		padding: padding,
 	}
 
 	return client
@@ -45,6 +84,10 @@ func NewRavencoin() *Client {
 		CachesCount:    3,
 		CachesLockMmap: false,
 
+		FullDataset:      false,
+		DatasetsCount:    1,
+		DatasetsLockMmap: false,
+
 		L1Enabled:       true,
 		L1CacheSize:     4096 * 4,
 		L1CacheNumItems: 4096,
@@ -53,9 +96,175 @@ func NewRavencoin() *Client {
 	return New(cfg)
 }
 
+// NewEvrmore returns an EvrProgPoW client, which pads the keccak states with
+// "EVRMORE-PROGPOW" and uses a 3Gb initial dataset with 12000 block epochs.
+func NewEvrmore() *Client {
+	var cfg = dag.Config{
+		Name:       "EVR",
+		Revision:   23,
+		StorageDir: common.DefaultDir(".powcache"),
+
+		DatasetInitBytes:   3 << 30,
+		DatasetGrowthBytes: 1 << 23,
+		CacheInitBytes:     1 << 24,
+		CacheGrowthBytes:   1 << 17,
+
+		MixBytes:        128,
+		DatasetParents:  512,
+		EpochLength:     12000,
+		SeedEpochLength: 12000,
+
+		CacheRounds:    3,
+		CachesCount:    3,
+		CachesLockMmap: false,
+
+		FullDataset:      false,
+		DatasetsCount:    1,
+		DatasetsLockMmap: false,
+
+		L1Enabled:       true,
+		L1CacheSize:     4096 * 4,
+		L1CacheNumItems: 4096,
+	}
+
+	return newClient(cfg, ravencoinCfg, evrmoreEvrprogpow)
+}
+
+// NewMeowcoin returns a MeowPoW client, which pads the keccak states with
+// "MEOWCOINMEOWPOW", uses a 4Gb initial dataset and a longer ProgPoW period
+// with fewer cache accesses and math operations per round.
+func NewMeowcoin() *Client {
+	var cfg = dag.Config{
+		Name:       "MEWC",
+		Revision:   23,
+		StorageDir: common.DefaultDir(".powcache"),
+
+		DatasetInitBytes:   4 << 30,
+		DatasetGrowthBytes: 1 << 23,
+		CacheInitBytes:     1 << 24,
+		CacheGrowthBytes:   1 << 17,
+
+		MixBytes:        128,
+		DatasetParents:  512,
+		EpochLength:     7500,
+		SeedEpochLength: 7500,
+
+		CacheRounds:    3,
+		CachesCount:    3,
+		CachesLockMmap: false,
+
+		FullDataset:      false,
+		DatasetsCount:    1,
+		DatasetsLockMmap: false,
+
+		L1Enabled:       true,
+		L1CacheSize:     4096 * 4,
+		L1CacheNumItems: 4096,
+	}
+
+	return newClient(cfg, meowcoinCfg, meowcoinMeowpow)
+}
+
+// Epoch returns the KawPoW epoch of the height.
+func (c *Client) Epoch(height uint64) uint64 {
+	return c.data.CalcEpoch(height)
+}
+
+// ChainEpoch returns the chain's epoch number, equal to Epoch for the KawPoW presets.
+func (c *Client) ChainEpoch(height uint64) uint64 {
+	return c.data.ChainEpoch(height)
+}
+
+// SeedHash returns the seed of the KawPoW cache of the epoch.
+func (c *Client) SeedHash(epoch uint64) []byte {
+	return c.data.EpochSeed(epoch)
+}
+
+// CacheSize returns the size in bytes of the KawPoW cache of the epoch.
+func (c *Client) CacheSize(epoch uint64) uint64 {
+	return c.data.CacheSize(epoch)
+}
+
+// DatasetSize returns the size in bytes of the KawPoW dataset of the epoch.
+func (c *Client) DatasetSize(epoch uint64) uint64 {
+	return c.data.DatasetSize(epoch)
+}
+
+// CachedEpochs returns the epochs whose KawPoW cache is held in memory.
+func (c *Client) CachedEpochs() []uint64 {
+	return c.data.CachedEpochs()
+}
+
+// SetEventHandler sets the function called on cache lifecycle events, such as
+// a stored file failing validation and being regenerated. It may be called
+// while the client is in use.
+func (c *Client) SetEventHandler(handler func(CacheEvent)) {
+	c.data.SetEventHandler(handler)
+}
+
+// StoredCaches returns the files of the chain in the storage directory. The
+// epochs of the files of older revisions are not resolved.
+func (c *Client) StoredCaches() ([]StoredCache, error) {
+	return c.data.StoredFiles()
+}
+
+// VerifyStoredCache compares the stored cache of the epoch with a freshly
+// generated copy, returning an error matching powerr.ErrCorruptedCache if
+// they differ.
+func (c *Client) VerifyStoredCache(ctx context.Context, epoch uint64) error {
+	return c.data.VerifyStoredCache(ctx, epoch)
+}
+
+// GenerateCaches stores the caches of the epochs from first to last inclusive
+// ahead of use, without keeping them in memory.
+func (c *Client) GenerateCaches(ctx context.Context, first, last uint64) error {
+	return c.data.GenerateStoredCaches(ctx, first, last)
+}
+
+// SetStorage sets where the caches and datasets are kept, memory mapped files
+// in ~/.powcache by default. It must be called before the client is used.
+func (c *Client) SetStorage(storage Storage) {
+	c.data.SetStorage(storage)
+}
+
+// SetCacheBudget limits the total memory of the caches held to roughly the
+// given number of bytes, evicting the least recently used ones beyond it.
+// Zero means only the number of caches is limited.
+func (c *Client) SetCacheBudget(bytes uint64) {
+	c.data.SetCachesMaxBytes(bytes)
+}
+
+// SetCacheLookAhead sets the number of epochs following the newest one used
+// whose caches are generated in the background, one by default. Zero disables
+// the look-ahead.
+func (c *Client) SetCacheLookAhead(epochs int) {
+	c.data.SetCachesLookAhead(epochs)
+}
+
+// PinEpoch generates the cache of the epoch and keeps it in memory until
+// UnpinEpoch, regardless of the cache budget.
+func (c *Client) PinEpoch(ctx context.Context, epoch uint64) error {
+	return c.data.PinCache(ctx, epoch)
+}
+
+// UnpinEpoch lets the cache of the epoch be evicted again.
+func (c *Client) UnpinEpoch(epoch uint64) {
+	c.data.UnpinCache(epoch)
+}
+
+// Close releases the KawPoW caches and datasets, which are loaded again if used.
+func (c *Client) Close() {
+	c.data.Close()
+}
+
+// SetFullDataset toggles computing KawPoW hashes from the full dataset.
+func (c *Client) SetFullDataset(enabled bool) {
+	c.data.SetFullDataset(enabled)
+}
+
 func (c *Client) Compute(hash []byte, height, nonce uint64) ([]byte, []byte, error) {
 	if len(hash) != 32 {
-		return nil, nil, fmt.Errorf("hash must be 32 bytes")
+		return nil, nil, &powerr.LengthError{Field: "hash", Want: 32, Have: len(hash)}
 	}
 
 	epoch := c.data.CalcEpoch(height)
@@ -63,8 +272,95 @@ func (c *Client) Compute(hash []byte, height, nonce uint64) ([]byte, []byte, err
 	cache := c.data.GetCache(epoch)
 	lookup := c.data.NewLookupFunc2048(cache, epoch)
 
-	mix, digest := kawpow(hash, height, nonce, size, lookup, cache.L1())
-	runtime.KeepAlive(cache)
+	mix, digest := kawpow(c.cfg, c.padding, hash, height, nonce, size, lookup, cache.L1())
+	cache.Release()
 
 	return mix, digest, nil
 }
+
+// Kernel generates the CUDA or OpenCL source of the ProgPoW loop of the
+// period of height, which GPU miners compile for every period.
+func (c *Client) Kernel(height uint64, lang KernelLanguage) (string, error) {
+	return progpow.GenerateKernel(c.cfg, height/c.cfg.PeriodLength, lang)
+}
+
+// SetWorkers sets the number of goroutines Search and ComputeBatch run on,
+// runtime.NumCPU() if workers is not positive (the default).
+func (c *Client) SetWorkers(workers int) {
+	c.workers = workers
+}
+
+// Search computes count nonces starting at startNonce and returns the lowest
+// one whose digest is less than or equal to target, or nil if none of them is.
+func (c *Client) Search(ctx context.Context, hash []byte, height, startNonce, count uint64, target *big.Int) (*SearchResult, error) {
+	if len(hash) != 32 {
+		return nil, &powerr.LengthError{Field: "hash", Want: 32, Have: len(hash)}
+	}
+
+	epoch := c.data.CalcEpoch(height)
+	size := c.data.DatasetSize(epoch)
+	cache, err := c.data.GetCacheContext(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	newCompute := func() search.ComputeFunc {
+		lookup := c.data.NewLookupFunc2048(cache, epoch)
+
+		return func(nonce uint64) ([]byte, []byte, error) {
+			mix, digest := kawpow(c.cfg, c.padding, hash, height, nonce, size, lookup, cache.L1())
+
+			return mix, digest, nil
+		}
+	}
+
+	result, err := search.Search(ctx, startNonce, count, c.workers, target, newCompute)
+	cache.Release()
+
+	return result, err
+}
+
+// ComputeBatch computes every job, grouping them by epoch so that the cache
+// of each epoch is fetched once and every worker reuses its lookup. The
+// results are in the order of the jobs, the error is only returned if ctx
+// is done before all of them are computed.
+func (c *Client) ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error) {
+	results := make([]ComputeResult, len(jobs))
+	groups := batch.GroupBy(len(jobs), func(i int) uint64 {
+		return c.data.CalcEpoch(jobs[i].Height)
+	})
+
+	for _, group := range groups {
+		indices := group.Indices
+		epoch := group.Key
+		size := c.data.DatasetSize(epoch)
+		cache, err := c.data.GetCacheContext(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+
+		newWorker := func() func(int) {
+			lookup := c.data.NewLookupFunc2048(cache, epoch)
+
+			return func(i int) {
+				index := indices[i]
+				job := jobs[index]
+				if len(job.Hash) != 32 {
+					results[index].Err = &powerr.LengthError{Field: "hash", Want: 32, Have: len(job.Hash)}
+					return
+				}
+
+				mix, digest := kawpow(c.cfg, c.padding, job.Hash, job.Height, job.Nonce, size, lookup, cache.L1())
+				results[index] = ComputeResult{Mix: mix, Digest: digest}
+			}
+		}
+
+		err = batch.Run(ctx, len(indices), c.workers, newWorker)
+		cache.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
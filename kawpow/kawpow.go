@@ -30,7 +30,65 @@ var ravencoinKawpow [15]uint32 = [15]uint32{
 	0x00000057, //W
 }
 
-func initialize(hash []byte, nonce uint64) ([25]uint32, uint64) {
+var evrmoreEvrprogpow [15]uint32 = [15]uint32{
+	0x00000045, //E
+	0x00000056, //V
+	0x00000052, //R
+	0x0000004D, //M
+	0x0000004F, //O
+	0x00000052, //R
+	0x00000045, //E
+	0x0000002D, //-
+	0x00000050, //P
+	0x00000052, //R
+	0x0000004F, //O
+	0x00000047, //G
+	0x00000050, //P
+	0x0000004F, //O
+	0x00000057, //W
+}
+
+var meowcoinMeowpow [15]uint32 = [15]uint32{
+	0x0000004D, //M
+	0x00000045, //E
+	0x0000004F, //O
+	0x00000057, //W
+	0x00000043, //C
+	0x0000004F, //O
+	0x00000049, //I
+	0x0000004E, //N
+	0x0000004D, //M
+	0x00000045, //E
+	0x0000004F, //O
+	0x00000057, //W
+	0x00000050, //P
+	0x0000004F, //O
+	0x00000057, //W
+}
+
+var ravencoinCfg = &progpow.Config{
+	PeriodLength:        3,
+	DagLoads:            4,
+	CacheBytes:          16 * 1024,
+	LaneCount:           16,
+	RegisterCount:       32,
+	RoundCount:          64,
+	RoundCacheAccesses:  11,
+	RoundMathOperations: 18,
+}
+
+var meowcoinCfg = &progpow.Config{
+	PeriodLength:        6,
+	DagLoads:            4,
+	CacheBytes:          16 * 1024,
+	LaneCount:           16,
+	RegisterCount:       32,
+	RoundCount:          64,
+	RoundCacheAccesses:  6,
+	RoundMathOperations: 9,
+}
+
+func initialize(hash []byte, nonce uint64, padding [15]uint32) ([25]uint32, uint64) {
 	var seed [25]uint32
 	for i := 0; i < 8; i++ {
 		seed[i] = binary.LittleEndian.Uint32(hash[i*4 : i*4+4])
@@ -40,7 +98,7 @@ func initialize(hash []byte, nonce uint64) ([25]uint32, uint64) {
 	seed[9] = uint32(nonce >> 32)
 
 	for i := 10; i < 25; i++ {
-		seed[i] = ravencoinKawpow[i-10]
+		seed[i] = padding[i-10]
 	}
 
 	crypto.KeccakF800(&seed)
@@ -50,7 +108,7 @@ func initialize(hash []byte, nonce uint64) ([25]uint32, uint64) {
 	return seed, seedHead
 }
 
-func finalize(seed [25]uint32, mixHash []byte) []byte {
+func finalize(seed [25]uint32, mixHash []byte, padding [15]uint32) []byte {
 	var state [25]uint32
 	for i := 0; i < 8; i++ {
 		state[i] = seed[i]
@@ -58,7 +116,7 @@ func finalize(seed [25]uint32, mixHash []byte) []byte {
 	}
 
 	for i := 16; i < 25; i++ {
-		state[i] = ravencoinKawpow[i-16]
+		state[i] = padding[i-16]
 	}
 
 	crypto.KeccakF800(&state)
@@ -66,21 +124,10 @@ func finalize(seed [25]uint32, mixHash []byte) []byte {
 	return convutil.Uint32ArrayToBytes(state[:8], binary.LittleEndian)
 }
 
-func kawpow(hash []byte, height, nonce, datasetSize uint64, lookup func(index uint32) []uint32, l1 []uint32) ([]byte, []byte) {
-	var cfg = &progpow.Config{
-		PeriodLength:        3,
-		DagLoads:            4,
-		CacheBytes:          16 * 1024,
-		LaneCount:           16,
-		RegisterCount:       32,
-		RoundCount:          64,
-		RoundCacheAccesses:  11,
-		RoundMathOperations: 18,
-	}
-
-	seed, seedHead := initialize(hash, nonce)
+func kawpow(cfg *progpow.Config, padding [15]uint32, hash []byte, height, nonce, datasetSize uint64, lookup func(index uint32) []uint32, l1 []uint32) ([]byte, []byte) {
+	seed, seedHead := initialize(hash, nonce, padding)
 	mixHash := progpow.Hash(cfg, height, seedHead, datasetSize, lookup, l1)
-	digest := finalize(seed, mixHash)
+	digest := finalize(seed, mixHash, padding)
 
 	return mixHash, digest
 }
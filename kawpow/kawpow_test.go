@@ -6,6 +6,7 @@ package kawpow
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/sencha-dev/powkit/internal/common/testutil"
@@ -124,3 +125,128 @@ func TestComputeRavencoin(t *testing.T) {
 		}
 	}
 }
+
+// @TODO: add Evrmore and Meowcoin mainnet blocks (header hash, height, nonce,
+// mix and digest), until then their presets are only checked to differ from
+// Ravencoin here and by their parameters in TestPresetParameters.
+func TestComputeVariants(t *testing.T) {
+	hash := testutil.MustDecodeHex("63155f732f2bf556967f906155b510c917e48e99685ead76ea83f4eca03ab12b")
+	ravencoin := NewRavencoin()
+
+	tests := []struct {
+		client *Client
+		height uint64
+		same   bool
+	}{
+		{
+			client: New(ravencoin.data.Config),
+			height: 49,
+			same:   true,
+		},
+		{
+			client: newClient(ravencoin.data.Config, ravencoinCfg, evrmoreEvrprogpow),
+			height: 49,
+			same:   false,
+		},
+		{
+			client: newClient(ravencoin.data.Config, meowcoinCfg, ravencoinKawpow),
+			height: 49,
+			same:   false,
+		},
+		{
+			client: NewEvrmore(),
+			height: 49,
+			same:   false,
+		},
+		{
+			client: NewMeowcoin(),
+			height: 49,
+			same:   false,
+		},
+	}
+
+	for i, tt := range tests {
+		mix, digest, err := tt.client.Compute(hash, tt.height, 0)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		}
+
+		rvnMix, rvnDigest, err := ravencoin.Compute(hash, tt.height, 0)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+			continue
+		}
+
+		same := bytes.Equal(mix, rvnMix) && bytes.Equal(digest, rvnDigest)
+		if same != tt.same {
+			t.Errorf("failed on %d: match with ravencoin mismatch: have %t, want %t", i, same, tt.same)
+		}
+	}
+}
+
+func TestPresetParameters(t *testing.T) {
+	tests := []struct {
+		client        *Client
+		padding       string
+		datasetInit   uint64
+		epochLength   uint64
+		periodLength  uint64
+		cacheAccesses int
+		mathOps       int
+	}{
+		// the lowercase r is in the reference constants of Ravencoin
+		{NewRavencoin(), "rAVENCOINKAWPOW", 1 << 30, 7500, 3, 11, 18},
+		{NewEvrmore(), "EVRMORE-PROGPOW", 3 << 30, 12000, 3, 11, 18},
+		{NewMeowcoin(), "MEOWCOINMEOWPOW", 4 << 30, 7500, 6, 6, 9},
+	}
+
+	for i, tt := range tests {
+		var padding []byte
+		for _, word := range tt.client.padding {
+			padding = append(padding, byte(word))
+		}
+
+		cfg := tt.client.data.Config
+		if string(padding) != tt.padding {
+			t.Errorf("failed on %d: padding mismatch: have %q, want %q", i, padding, tt.padding)
+		} else if cfg.DatasetInitBytes != tt.datasetInit {
+			t.Errorf("failed on %d: initial dataset size mismatch: have %d, want %d", i, cfg.DatasetInitBytes, tt.datasetInit)
+		} else if epoch := tt.client.Epoch(tt.epochLength); cfg.EpochLength != tt.epochLength || epoch != 1 {
+			t.Errorf("failed on %d: epoch length mismatch: have %d, want %d", i, cfg.EpochLength, tt.epochLength)
+		} else if tt.client.cfg.PeriodLength != tt.periodLength {
+			t.Errorf("failed on %d: period length mismatch: have %d, want %d", i, tt.client.cfg.PeriodLength, tt.periodLength)
+		} else if tt.client.cfg.RoundCacheAccesses != tt.cacheAccesses || tt.client.cfg.RoundMathOperations != tt.mathOps {
+			t.Errorf("failed on %d: operations mismatch: have %d/%d, want %d/%d", i, tt.client.cfg.RoundCacheAccesses,
+				tt.client.cfg.RoundMathOperations, tt.cacheAccesses, tt.mathOps)
+		}
+	}
+}
+
+func TestComputeBatch(t *testing.T) {
+	hash := testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff")
+	client := NewRavencoin()
+	client.SetWorkers(3)
+
+	var jobs []ComputeJob
+	for nonce := uint64(0); nonce < 40; nonce++ {
+		jobs = append(jobs, ComputeJob{Hash: hash, Height: (nonce % 2) * 7500, Nonce: nonce})
+	}
+	jobs = append(jobs, ComputeJob{Hash: hash[:31], Height: 0, Nonce: 0})
+
+	results, err := client.ComputeBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, job := range jobs {
+		mix, digest, err := client.Compute(job.Hash, job.Height, job.Nonce)
+		if (err != nil) != (results[i].Err != nil) {
+			t.Errorf("failed on %d: error mismatch: have %v, want %v", i, results[i].Err, err)
+		} else if bytes.Compare(results[i].Mix, mix) != 0 {
+			t.Errorf("failed on %d: mix mismatch: have %x, want %x", i, results[i].Mix, mix)
+		} else if bytes.Compare(results[i].Digest, digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, results[i].Digest, digest)
+		}
+	}
+}
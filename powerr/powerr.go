@@ -0,0 +1,152 @@
+// Package powerr defines the errors shared by every algorithm, so that
+// rejected shares can be classified with errors.Is and errors.As without
+// matching on error strings.
+package powerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidInput matches every error caused by malformed input
+	// (LengthError, InputError and BudgetError).
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrInvalidSolution matches every error caused by a solution that
+	// fails verification (SolutionError).
+	ErrInvalidSolution = errors.New("invalid solution")
+
+	// ErrUnsupportedVariant matches every error caused by an unknown
+	// algorithm, coin or variant (VariantError).
+	ErrUnsupportedVariant = errors.New("unsupported variant")
+
+	// ErrCorruptedCache matches every error caused by a stored cache file
+	// that does not hold the content it should.
+	ErrCorruptedCache = errors.New("corrupted cache file")
+)
+
+// LengthError is returned when an input does not have the expected length.
+type LengthError struct {
+	Field string // Name of the input, such as "hash" or "soln"
+	Want  int
+	Have  int
+	Unit  string // Unit of the lengths, bytes if empty
+}
+
+func (e *LengthError) Error() string {
+	unit := e.Unit
+	if unit == "" {
+		unit = "bytes"
+	}
+
+	return fmt.Sprintf("%s must be %d %s", e.Field, e.Want, unit)
+}
+
+func (e *LengthError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// InputError is returned when an input is malformed in any other way.
+type InputError struct {
+	Field  string
+	Reason string
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Reason)
+}
+
+func (e *InputError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// BudgetError is returned when solving an input would use more memory
+// than the budget set on the solver.
+type BudgetError struct {
+	Budget uint64 // Budget in bytes
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("memory budget of %d bytes exceeded", e.Budget)
+}
+
+func (e *BudgetError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// Reason is the code of the check a solution failed.
+type Reason int
+
+const (
+	// Equihash and BeamHash.
+	ReasonCollision Reason = iota + 1
+	ReasonOutOfOrder
+	ReasonDuplicateIndices
+
+	// Cuckoo Cycle.
+	ReasonPowTooBig
+	ReasonPowTooSmall
+	ReasonPowNotMatching
+	ReasonPowBranch
+	ReasonPowDeadEnd
+	ReasonPowShortCycle
+	ReasonPowUnbalanced
+)
+
+var reasonStrings = map[Reason]string{
+	ReasonCollision:        "collision",
+	ReasonOutOfOrder:       "out of order",
+	ReasonDuplicateIndices: "duplicate indices",
+	ReasonPowTooBig:        "pow too big",
+	ReasonPowTooSmall:      "pow too small",
+	ReasonPowNotMatching:   "pow not matching",
+	ReasonPowBranch:        "pow branch",
+	ReasonPowDeadEnd:       "pow dead end",
+	ReasonPowShortCycle:    "pow short cycle",
+	ReasonPowUnbalanced:    "pow unbalanced",
+}
+
+func (r Reason) String() string {
+	if s, ok := reasonStrings[r]; ok {
+		return s
+	}
+
+	return fmt.Sprintf("unknown reason %d", int(r))
+}
+
+// SolutionError is returned when a solution fails one of the checks of
+// its algorithm. It matches ErrInvalidSolution and any SolutionError
+// with the same reason.
+type SolutionError struct {
+	Reason Reason
+}
+
+func (e *SolutionError) Error() string {
+	return e.Reason.String()
+}
+
+func (e *SolutionError) Is(target error) bool {
+	if target == ErrInvalidSolution {
+		return true
+	}
+
+	t, ok := target.(*SolutionError)
+
+	return ok && t.Reason == e.Reason
+}
+
+// VariantError is returned for an algorithm, coin or variant that is not
+// supported.
+type VariantError struct {
+	Kind string // What was looked up, such as "hasher" or "cuckoo variant"
+	Name string
+}
+
+func (e *VariantError) Error() string {
+	return fmt.Sprintf("unknown %s %s", e.Kind, e.Name)
+}
+
+func (e *VariantError) Is(target error) bool {
+	return target == ErrUnsupportedVariant
+}
@@ -0,0 +1,81 @@
+package powerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		err      error
+		sentinel error
+		message  string
+	}{
+		{
+			err:      &LengthError{Field: "hash", Want: 32, Have: 31},
+			sentinel: ErrInvalidInput,
+			message:  "hash must be 32 bytes",
+		},
+		{
+			err:      &LengthError{Field: "sols", Want: 42, Have: 41, Unit: "uint64s"},
+			sentinel: ErrInvalidInput,
+			message:  "sols must be 42 uint64s",
+		},
+		{
+			err:      &InputError{Field: "soln", Reason: "must be a multiple of 4 bytes"},
+			sentinel: ErrInvalidInput,
+			message:  "soln must be a multiple of 4 bytes",
+		},
+		{
+			err:      &BudgetError{Budget: 1 << 16},
+			sentinel: ErrInvalidInput,
+			message:  "memory budget of 65536 bytes exceeded",
+		},
+		{
+			err:      &SolutionError{Reason: ReasonDuplicateIndices},
+			sentinel: ErrInvalidSolution,
+			message:  "duplicate indices",
+		},
+		{
+			err:      fmt.Errorf("share rejected: %w", &SolutionError{Reason: ReasonPowBranch}),
+			sentinel: ErrInvalidSolution,
+			message:  "share rejected: pow branch",
+		},
+		{
+			err:      &VariantError{Kind: "hasher", Name: "XYZ"},
+			sentinel: ErrUnsupportedVariant,
+			message:  "unknown hasher XYZ",
+		},
+	}
+
+	sentinels := []error{ErrInvalidInput, ErrInvalidSolution, ErrUnsupportedVariant}
+	for i, tt := range tests {
+		if tt.err.Error() != tt.message {
+			t.Errorf("failed on %d: message mismatch: have %s, want %s", i, tt.err.Error(), tt.message)
+		}
+
+		for _, sentinel := range sentinels {
+			if errors.Is(tt.err, sentinel) != (sentinel == tt.sentinel) {
+				t.Errorf("failed on %d: unexpected match result for %v", i, sentinel)
+			}
+		}
+	}
+}
+
+func TestSolutionErrorReason(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &SolutionError{Reason: ReasonCollision})
+
+	var solErr *SolutionError
+	if !errors.As(err, &solErr) {
+		t.Fatalf("expected solution error")
+	} else if solErr.Reason != ReasonCollision {
+		t.Errorf("reason mismatch: have %v, want %v", solErr.Reason, ReasonCollision)
+	}
+
+	if !errors.Is(err, &SolutionError{Reason: ReasonCollision}) {
+		t.Errorf("expected match for the same reason")
+	} else if errors.Is(err, &SolutionError{Reason: ReasonOutOfOrder}) {
+		t.Errorf("unexpected match for a different reason")
+	}
+}
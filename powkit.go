@@ -0,0 +1,191 @@
+package powkit
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/sencha-dev/powkit/autolykos2"
+	"github.com/sencha-dev/powkit/beamhashiii"
+	"github.com/sencha-dev/powkit/cuckoo"
+	"github.com/sencha-dev/powkit/eaglesong"
+	"github.com/sencha-dev/powkit/equihash"
+	"github.com/sencha-dev/powkit/ethash"
+	"github.com/sencha-dev/powkit/firopow"
+	"github.com/sencha-dev/powkit/heavyhash"
+	"github.com/sencha-dev/powkit/internal/batch"
+	"github.com/sencha-dev/powkit/internal/dag"
+	"github.com/sencha-dev/powkit/internal/progpow"
+	"github.com/sencha-dev/powkit/kawpow"
+	"github.com/sencha-dev/powkit/octopus"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+// Hasher is implemented by every algorithm that produces a digest from
+// a header hash, a height and a nonce. The mix is nil for algorithms
+// that do not produce one.
+type Hasher interface {
+	Compute(hash []byte, height, nonce uint64) ([]byte, []byte, error)
+}
+
+// Verifier is implemented by every algorithm that validates a solution
+// found for a header.
+type Verifier interface {
+	Verify(header, soln []byte) (bool, error)
+}
+
+// ComputeJob, ComputeResult, VerifyJob and VerifyResult are the jobs and
+// results of the batch interfaces, shared by the algorithm packages.
+type (
+	ComputeJob    = batch.ComputeJob
+	ComputeResult = batch.ComputeResult
+	VerifyJob     = batch.VerifyJob
+	VerifyResult  = batch.VerifyResult
+)
+
+// StoredCache is a cache, L1 cache or dataset file in the storage directory
+// of a DAG based hasher.
+type StoredCache = dag.StoredFile
+
+// CacheEvent reports a step in the lifecycle of the cache of a DAG based
+// hasher, see DAGHasher.SetEventHandler.
+type CacheEvent = dag.Event
+
+// CacheEventType is the kind of a CacheEvent.
+type CacheEventType = dag.EventType
+
+const (
+	CacheStarted   CacheEventType = dag.EventCacheStarted   // Cache generation started
+	CacheProgress  CacheEventType = dag.EventCacheProgress  // Cache generation progressed, see CacheEvent.Percent
+	CacheWritten   CacheEventType = dag.EventCacheWritten   // Cache was generated and written to disk
+	CacheLoaded    CacheEventType = dag.EventCacheLoaded    // Cache was loaded from an existing file
+	CacheEvicted   CacheEventType = dag.EventCacheEvicted   // Cache was evicted from memory
+	CacheCorrupted CacheEventType = dag.EventCacheCorrupted // Stored file failed validation and is regenerated, see CacheEvent.Err
+)
+
+// BatchHasher is implemented by every registered hasher. The results are in
+// the order of the jobs, the error is only returned if ctx is done first.
+type BatchHasher interface {
+	Hasher
+	ComputeBatch(ctx context.Context, jobs []ComputeJob) ([]ComputeResult, error)
+}
+
+// BatchVerifier is implemented by every registered verifier. The results are
+// in the order of the jobs, the error is only returned if ctx is done first.
+type BatchVerifier interface {
+	Verifier
+	VerifyBatch(ctx context.Context, jobs []VerifyJob) ([]VerifyResult, error)
+}
+
+// DAGHasher is implemented by the hashers of DAG based algorithms. Epoch
+// numbers the epochs sequentially and is what the methods taking an epoch
+// expect, while ChainEpoch is the chain's epoch number, the height divided by
+// the epoch length in effect; the two differ after an epoch length change such
+// as Ethereum Classic's. Close stops the background generation of future
+// caches and releases the caches and datasets held in memory, unmapping their
+// files once the calls in progress return. The hasher remains usable, loading
+// the caches again.
+type DAGHasher interface {
+	Hasher
+	Epoch(height uint64) uint64
+	ChainEpoch(height uint64) uint64
+	SeedHash(epoch uint64) []byte
+	CacheSize(epoch uint64) uint64
+	DatasetSize(epoch uint64) uint64
+	CachedEpochs() []uint64
+	StoredCaches() ([]StoredCache, error)
+	VerifyStoredCache(ctx context.Context, epoch uint64) error
+	GenerateCaches(ctx context.Context, first, last uint64) error
+	SetEventHandler(handler func(CacheEvent))
+	SetStorage(storage Storage)
+	SetCacheBudget(bytes uint64)
+	SetCacheLookAhead(epochs int)
+	PinEpoch(ctx context.Context, epoch uint64) error
+	UnpinEpoch(epoch uint64)
+	Close()
+}
+
+// KernelLanguage is the language of the source written by
+// KernelGenerator.Kernel.
+type KernelLanguage = progpow.KernelLanguage
+
+const (
+	KernelCUDA   KernelLanguage = progpow.KernelCUDA   // CUDA source for NVIDIA GPUs
+	KernelOpenCL KernelLanguage = progpow.KernelOpenCL // OpenCL source for AMD and other GPUs
+)
+
+// KernelGenerator is implemented by the hashers of ProgPoW based algorithms,
+// which generate the GPU source of the ProgPoW loop of the period of a
+// height, identical in behavior to the loop they verify with.
+type KernelGenerator interface {
+	Kernel(height uint64, lang KernelLanguage) (string, error)
+}
+
+var hashers = map[string]func() Hasher{
+	"ETH":  func() Hasher { return ethash.NewEthereum() },
+	"ETC":  func() Hasher { return ethash.NewEthereumClassic() },
+	"ETHW": func() Hasher { return ethash.NewEthereumPoW() },
+	"CLO":  func() Hasher { return ethash.NewCallisto() },
+	"EXP":  func() Hasher { return ethash.NewExpanse() },
+	"UBQ":  func() Hasher { return ethash.NewUbiq() },
+	"HYP":  func() Hasher { return ethash.NewHypra() },
+	"RVN":  func() Hasher { return kawpow.NewRavencoin() },
+	"EVR":  func() Hasher { return kawpow.NewEvrmore() },
+	"MEWC": func() Hasher { return kawpow.NewMeowcoin() },
+	"FIRO": func() Hasher { return firopow.NewFiro() },
+	"CFX":  func() Hasher { return &octopusHasher{octopus.NewConflux()} },
+	"ERG":  func() Hasher { return &autolykos2Hasher{autolykos2.NewErgo()} },
+	"KAS":  func() Hasher { return &heavyhashHasher{heavyhash.NewKaspa()} },
+	"CKB":  func() Hasher { return &eaglesongHasher{eaglesong.NewNervos()} },
+}
+
+var verifiers = map[string]func() Verifier{
+	"ZEC":  func() Verifier { return equihash.NewZCash() },
+	"FLUX": func() Verifier { return equihash.NewFlux() },
+	"BEAM": func() Verifier { return beamhashiii.NewBeam() },
+	"AE":   func() Verifier { return &cuckooVerifier{cuckoo.NewAeternity()} },
+	"CTXC": func() Verifier { return &cuckooVerifier{cuckoo.NewCortex()} },
+}
+
+// NewHasher returns a new preconfigured hasher for the given coin. DAG based
+// hashers keep their caches per instance, so the result should be reused.
+func NewHasher(name string) (Hasher, error) {
+	constructor, ok := hashers[strings.ToUpper(name)]
+	if !ok {
+		return nil, &powerr.VariantError{Kind: "hasher", Name: name}
+	}
+
+	return constructor(), nil
+}
+
+// NewVerifier returns a new preconfigured verifier for the given coin.
+func NewVerifier(name string) (Verifier, error) {
+	constructor, ok := verifiers[strings.ToUpper(name)]
+	if !ok {
+		return nil, &powerr.VariantError{Kind: "verifier", Name: name}
+	}
+
+	return constructor(), nil
+}
+
+// Hashers returns the sorted names of all registered hashers.
+func Hashers() []string {
+	names := make([]string, 0, len(hashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Verifiers returns the sorted names of all registered verifiers.
+func Verifiers() []string {
+	names := make([]string, 0, len(verifiers))
+	for name := range verifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,367 @@
+package powkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/sencha-dev/powkit/autolykos2"
+	"github.com/sencha-dev/powkit/cuckoo"
+	"github.com/sencha-dev/powkit/ethash"
+	"github.com/sencha-dev/powkit/firopow"
+	"github.com/sencha-dev/powkit/heavyhash"
+	"github.com/sencha-dev/powkit/internal/common/testutil"
+	"github.com/sencha-dev/powkit/internal/search"
+	"github.com/sencha-dev/powkit/kawpow"
+	"github.com/sencha-dev/powkit/octopus"
+	"github.com/sencha-dev/powkit/powerr"
+	"github.com/sencha-dev/powkit/progpow"
+)
+
+func TestRegistry(t *testing.T) {
+	dagHashers := map[string]bool{
+		"ETH": true, "ETC": true, "ETHW": true, "CLO": true, "EXP": true, "UBQ": true, "HYP": true,
+		"RVN": true, "EVR": true, "MEWC": true, "FIRO": true, "CFX": true,
+	}
+
+	kernelGenerators := map[string]bool{"RVN": true, "EVR": true, "MEWC": true, "FIRO": true}
+
+	for _, name := range Hashers() {
+		hasher, err := NewHasher(name)
+		if err != nil {
+			t.Errorf("failed on %s: %v", name, err)
+			continue
+		}
+
+		if _, ok := hasher.(BatchHasher); !ok {
+			t.Errorf("failed on %s: not a batch hasher", name)
+		}
+
+		if _, ok := hasher.(DAGHasher); ok != dagHashers[name] {
+			t.Errorf("failed on %s: dag hasher mismatch: have %t, want %t", name, ok, dagHashers[name])
+		}
+
+		if _, ok := hasher.(KernelGenerator); ok != kernelGenerators[name] {
+			t.Errorf("failed on %s: kernel generator mismatch: have %t, want %t", name, ok, kernelGenerators[name])
+		}
+	}
+
+	for _, name := range Verifiers() {
+		verifier, err := NewVerifier(name)
+		if err != nil {
+			t.Errorf("failed on %s: %v", name, err)
+			continue
+		}
+
+		if _, ok := verifier.(BatchVerifier); !ok {
+			t.Errorf("failed on %s: not a batch verifier", name)
+		}
+	}
+
+	if _, err := NewHasher("unknown"); err == nil {
+		t.Errorf("expected error for unknown hasher")
+	}
+
+	if _, err := NewVerifier("unknown"); err == nil {
+		t.Errorf("expected error for unknown verifier")
+	}
+}
+
+func TestHasherKaspa(t *testing.T) {
+	tests := []struct {
+		hash      []byte
+		timestamp uint64
+		nonce     uint64
+		digest    []byte
+	}{
+		{
+			hash:      testutil.MustDecodeHex("81553a695a0588998c413792e74ce8b8f8a096d64b3ee47387372434485c0b6f"),
+			timestamp: 0x000001848ca87c49,
+			nonce:     0x2f8400000eba167c,
+			digest:    testutil.MustDecodeHex("000000001726686e851f02c584d7cc8a8fbe5938ecdb3ffa2ba16c260ee1fc40"),
+		},
+	}
+
+	hasher, err := NewHasher("kas")
+	if err != nil {
+		t.Fatalf("failed to create hasher: %v", err)
+	}
+
+	for i, tt := range tests {
+		mix, digest, err := hasher.Compute(tt.hash, tt.timestamp, tt.nonce)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if mix != nil {
+			t.Errorf("failed on %d: expected nil mix", i)
+		} else if bytes.Compare(digest, tt.digest) != 0 {
+			t.Errorf("failed on %d: have %x, want %x", i, digest, tt.digest)
+		}
+	}
+}
+
+func TestVerifierCortex(t *testing.T) {
+	tests := []struct {
+		header []byte
+		sols   []uint32
+	}{
+		{
+			header: testutil.MustDecodeHex("6281a031a95a7669e42cf56d46b5d921b067ace29c46c89fa2698f3b895d6fcb21208e4e00000165"),
+			sols: []uint32{
+				0x017ca085, 0x0181ca71, 0x096b8b98, 0x09d3a607, 0x0b6bb4c8, 0x0c9bbecb, 0x10d1c645, 0x13ba80dc,
+				0x13cb4dc9, 0x15ebc37d, 0x164de862, 0x16a7906a, 0x18c28113, 0x199e50ca, 0x1ba70932, 0x1bc435b1,
+				0x1caad714, 0x1d94ccd4, 0x1da4b49d, 0x1eff189e, 0x2030c2cf, 0x2084a6c3, 0x2111e51e, 0x241ff2d0,
+				0x26bb0111, 0x275fd4a1, 0x27654850, 0x291041de, 0x2a4c1e5b, 0x2a8e54e1, 0x2ba12d29, 0x2d16cbc0,
+				0x2e9e0df8, 0x3209259d, 0x32751e22, 0x33107850, 0x332b35f9, 0x33a134d4, 0x354fc224, 0x384052fb,
+				0x38cdb22e, 0x3e665fed,
+			},
+		},
+	}
+
+	verifier, err := NewVerifier("CTXC")
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	for i, tt := range tests {
+		soln := make([]byte, len(tt.sols)*4)
+		for j, sol := range tt.sols {
+			binary.LittleEndian.PutUint32(soln[j*4:], sol)
+		}
+
+		valid, err := verifier.Verify(tt.header, soln)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if !valid {
+			t.Errorf("failed on %d: invalid solution", i)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cortex := []uint32{
+		0x0181ca71, 0x017ca085, 0x096b8b98, 0x09d3a607, 0x0b6bb4c8, 0x0c9bbecb, 0x10d1c645, 0x13ba80dc,
+		0x13cb4dc9, 0x15ebc37d, 0x164de862, 0x16a7906a, 0x18c28113, 0x199e50ca, 0x1ba70932, 0x1bc435b1,
+		0x1caad714, 0x1d94ccd4, 0x1da4b49d, 0x1eff189e, 0x2030c2cf, 0x2084a6c3, 0x2111e51e, 0x241ff2d0,
+		0x26bb0111, 0x275fd4a1, 0x27654850, 0x291041de, 0x2a4c1e5b, 0x2a8e54e1, 0x2ba12d29, 0x2d16cbc0,
+		0x2e9e0df8, 0x3209259d, 0x32751e22, 0x33107850, 0x332b35f9, 0x33a134d4, 0x354fc224, 0x384052fb,
+		0x38cdb22e, 0x3e665fed,
+	}
+	cortexSoln := make([]byte, len(cortex)*4)
+	for i, sol := range cortex {
+		binary.LittleEndian.PutUint32(cortexSoln[i*4:], sol)
+	}
+
+	mustHasher := func(name string) Hasher {
+		hasher, err := NewHasher(name)
+		if err != nil {
+			t.Fatalf("failed to create hasher %s: %v", name, err)
+		}
+		return hasher
+	}
+
+	mustVerifier := func(name string) Verifier {
+		verifier, err := NewVerifier(name)
+		if err != nil {
+			t.Fatalf("failed to create verifier %s: %v", name, err)
+		}
+		return verifier
+	}
+
+	tests := []struct {
+		run      func() error
+		sentinel error
+		target   error
+	}{
+		{
+			run: func() error {
+				_, err := NewHasher("unknown")
+				return err
+			},
+			sentinel: powerr.ErrUnsupportedVariant,
+		},
+		{
+			run: func() error {
+				_, _, err := mustHasher("ETH").Compute(make([]byte, 31), 0, 0)
+				return err
+			},
+			sentinel: powerr.ErrInvalidInput,
+		},
+		{
+			run: func() error {
+				_, err := mustVerifier("CTXC").Verify(make([]byte, 40), make([]byte, 3))
+				return err
+			},
+			sentinel: powerr.ErrInvalidInput,
+		},
+		{
+			run: func() error {
+				header := testutil.MustDecodeHex("6281a031a95a7669e42cf56d46b5d921b067ace29c46c89fa2698f3b895d6fcb21208e4e00000165")
+				_, err := mustVerifier("CTXC").Verify(header, cortexSoln)
+				return err
+			},
+			sentinel: powerr.ErrInvalidSolution,
+			target:   cuckoo.ErrPowTooSmall,
+		},
+		{
+			run: func() error {
+				_, err := mustVerifier("ZEC").Verify(make([]byte, 140), make([]byte, 1344))
+				return err
+			},
+			sentinel: powerr.ErrInvalidSolution,
+			target:   &powerr.SolutionError{Reason: powerr.ReasonDuplicateIndices},
+		},
+		{
+			run: func() error {
+				_, err := mustVerifier("BEAM").Verify(make([]byte, 40), make([]byte, 100))
+				return err
+			},
+			sentinel: powerr.ErrInvalidInput,
+		},
+	}
+
+	for i, tt := range tests {
+		err := tt.run()
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("failed on %d: have %v, want %v", i, err, tt.sentinel)
+		} else if tt.target != nil && !errors.Is(err, tt.target) {
+			t.Errorf("failed on %d: have %v, want %v", i, err, tt.target)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	hash := testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff")
+	ctx := context.Background()
+
+	ethashClient := ethash.NewEthereum()
+	kawpowClient := kawpow.NewRavencoin()
+	firopowClient := firopow.NewFiro()
+	progpowClient := progpow.NewProgPoW094()
+	octopusClient := octopus.NewConflux()
+	autolykos2Client := autolykos2.NewErgo()
+	heavyhashClient := heavyhash.NewKaspa()
+
+	tests := []struct {
+		name       string
+		setWorkers func(int)
+		compute    func(nonce uint64) ([]byte, error)
+		search     func(startNonce, count uint64, target *big.Int) (*search.Result, error)
+	}{
+		{
+			name:       "ethash",
+			setWorkers: ethashClient.SetWorkers,
+			compute: func(nonce uint64) ([]byte, error) {
+				_, digest, err := ethashClient.Compute(hash, 0, nonce)
+				return digest, err
+			},
+			search: func(startNonce, count uint64, target *big.Int) (*search.Result, error) {
+				return ethashClient.Search(ctx, hash, 0, startNonce, count, target)
+			},
+		},
+		{
+			name:       "kawpow",
+			setWorkers: kawpowClient.SetWorkers,
+			compute: func(nonce uint64) ([]byte, error) {
+				_, digest, err := kawpowClient.Compute(hash, 0, nonce)
+				return digest, err
+			},
+			search: func(startNonce, count uint64, target *big.Int) (*search.Result, error) {
+				return kawpowClient.Search(ctx, hash, 0, startNonce, count, target)
+			},
+		},
+		{
+			name:       "firopow",
+			setWorkers: firopowClient.SetWorkers,
+			compute: func(nonce uint64) ([]byte, error) {
+				_, digest, err := firopowClient.Compute(hash, 0, nonce)
+				return digest, err
+			},
+			search: func(startNonce, count uint64, target *big.Int) (*search.Result, error) {
+				return firopowClient.Search(ctx, hash, 0, startNonce, count, target)
+			},
+		},
+		{
+			name:       "progpow",
+			setWorkers: progpowClient.SetWorkers,
+			compute: func(nonce uint64) ([]byte, error) {
+				_, digest, err := progpowClient.Compute(hash, 0, nonce)
+				return digest, err
+			},
+			search: func(startNonce, count uint64, target *big.Int) (*search.Result, error) {
+				return progpowClient.Search(ctx, hash, 0, startNonce, count, target)
+			},
+		},
+		{
+			name:       "octopus",
+			setWorkers: octopusClient.SetWorkers,
+			compute: func(nonce uint64) ([]byte, error) {
+				return octopusClient.Compute(hash, 0, nonce)
+			},
+			search: func(startNonce, count uint64, target *big.Int) (*search.Result, error) {
+				return octopusClient.Search(ctx, hash, 0, startNonce, count, target)
+			},
+		},
+		{
+			name:       "autolykos2",
+			setWorkers: autolykos2Client.SetWorkers,
+			compute: func(nonce uint64) ([]byte, error) {
+				return autolykos2Client.Compute(hash, 0, nonce)
+			},
+			search: func(startNonce, count uint64, target *big.Int) (*search.Result, error) {
+				return autolykos2Client.Search(ctx, hash, 0, startNonce, count, target)
+			},
+		},
+		{
+			name:       "heavyhash",
+			setWorkers: heavyhashClient.SetWorkers,
+			compute: func(nonce uint64) ([]byte, error) {
+				return heavyhashClient.Compute(hash, 0, nonce)
+			},
+			search: func(startNonce, count uint64, target *big.Int) (*search.Result, error) {
+				return heavyhashClient.Search(ctx, hash, 0, startNonce, count, target)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.setWorkers(4)
+
+		// the target is the lowest digest of the range, so that exactly one
+		// nonce meets it
+		var want uint64
+		var min []byte
+		for nonce := uint64(100); nonce < 132; nonce++ {
+			digest, err := tt.compute(nonce)
+			if err != nil {
+				t.Fatalf("failed on %s: %v", tt.name, err)
+			} else if min == nil || bytes.Compare(digest, min) < 0 {
+				want, min = nonce, digest
+			}
+		}
+
+		target := new(big.Int).SetBytes(min)
+		result, err := tt.search(100, 32, target)
+		if err != nil {
+			t.Errorf("failed on %s: %v", tt.name, err)
+			continue
+		} else if result == nil {
+			t.Errorf("failed on %s: no nonce found", tt.name)
+			continue
+		} else if result.Nonce != want {
+			t.Errorf("failed on %s: nonce mismatch: have %d, want %d", tt.name, result.Nonce, want)
+		} else if bytes.Compare(result.Digest, min) != 0 {
+			t.Errorf("failed on %s: digest mismatch: have %x, want %x", tt.name, result.Digest, min)
+		}
+
+		target.Sub(target, big.NewInt(1))
+		result, err = tt.search(100, 32, target)
+		if err != nil {
+			t.Errorf("failed on %s: %v", tt.name, err)
+		} else if result != nil {
+			t.Errorf("failed on %s: unexpected nonce %d found", tt.name, result.Nonce)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package progpow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sencha-dev/powkit/internal/common/testutil"
+	"github.com/sencha-dev/powkit/powerr"
+)
+
+func TestCompute092(t *testing.T) {
+	tests := []struct {
+		height uint64
+		nonce  uint64
+		hash   []byte
+		mix    []byte
+		digest []byte
+	}{
+		{
+			height: 0,
+			nonce:  0x0000000000000000,
+			hash:   testutil.MustDecodeHex("0000000000000000000000000000000000000000000000000000000000000000"),
+			mix:    testutil.MustDecodeHex("faeb1be51075b03a4ff44b335067951ead07a3b078539ace76fd56fc410557a3"),
+			digest: testutil.MustDecodeHex("63155f732f2bf556967f906155b510c917e48e99685ead76ea83f4eca03ab12b"),
+		},
+		{
+			height: 49,
+			nonce:  0x0000000006ff2c47,
+			hash:   testutil.MustDecodeHex("63155f732f2bf556967f906155b510c917e48e99685ead76ea83f4eca03ab12b"),
+			mix:    testutil.MustDecodeHex("c789c1180f890ec555ff42042913465481e8e6bc512cb981e1c1108dc3f2227d"),
+			digest: testutil.MustDecodeHex("9e7248f20914913a73d80a70174c331b1d34f260535ac3631d770e656b5dd922"),
+		},
+		{
+			// test vector of the 0.9.2 specification (EIP-1057)
+			height: 30000,
+			nonce:  0x123456789abcdef0,
+			hash:   testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff"),
+			mix:    testutil.MustDecodeHex("11f19805c58ab46610ff9c719dcf0a5f18fa2f1605798eef770c47219274767d"),
+			digest: testutil.MustDecodeHex("5b7ccd472dbefdd95b895cac8ece67ff0deb5a6bd2ecc6e162383d00c3728ece"),
+		},
+	}
+
+	client := NewProgPoW092()
+	for i, tt := range tests {
+		mix, digest, err := client.Compute(tt.hash, tt.height, tt.nonce)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if bytes.Compare(mix, tt.mix) != 0 {
+			t.Errorf("failed on %d: mix mismatch: have %x, want %x", i, mix, tt.mix)
+		} else if bytes.Compare(digest, tt.digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, digest, tt.digest)
+		}
+	}
+}
+
+func TestCompute093(t *testing.T) {
+	tests := []struct {
+		height uint64
+		nonce  uint64
+		hash   []byte
+		mix    []byte
+		digest []byte
+	}{
+		{
+			// test vector of the 0.9.3 specification
+			height: 30000,
+			nonce:  0x123456789abcdef0,
+			hash:   testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff"),
+			mix:    testutil.MustDecodeHex("6018c151b0f9895ebe44a4ca6ce2829e5ba6ae1a68a4ccd05a67ac01219655c1"),
+			digest: testutil.MustDecodeHex("34d8436444aa5c61761ce0bcce0f11401df2eace77f5c14ba7039b86b5800c08"),
+		},
+	}
+
+	client := NewProgPoW093()
+	for i, tt := range tests {
+		mix, digest, err := client.Compute(tt.hash, tt.height, tt.nonce)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if bytes.Compare(mix, tt.mix) != 0 {
+			t.Errorf("failed on %d: mix mismatch: have %x, want %x", i, mix, tt.mix)
+		} else if bytes.Compare(digest, tt.digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, digest, tt.digest)
+		}
+	}
+}
+
+func TestCompute094(t *testing.T) {
+	tests := []struct {
+		height uint64
+		nonce  uint64
+		hash   []byte
+		mix    []byte
+		digest []byte
+	}{
+		{
+			height: 0,
+			nonce:  0x123456789abcdef0,
+			hash:   testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff"),
+			mix:    testutil.MustDecodeHex("c2e883b6876ec4cc514b9cea269f343095619faf9f2edcafb3fcf6928fa58141"),
+			digest: testutil.MustDecodeHex("fa70fbf9979f80ec3db2c3f118a5e683fcf5f54ea7edc41b0b5d336508694cb8"),
+		},
+		{
+			height: 49,
+			nonce:  0x0000000000000001,
+			hash:   testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff"),
+			mix:    testutil.MustDecodeHex("1704a993e5a8603615b964990253896681da83ddd10c0e6e8fee2f273fa2a961"),
+			digest: testutil.MustDecodeHex("528dff2f543825030a8e0943013de7bc6a4b7c203c7398607811176b03ce70f4"),
+		},
+	}
+
+	client := NewProgPoW094()
+	for i, tt := range tests {
+		mix, digest, err := client.Compute(tt.hash, tt.height, tt.nonce)
+		if err != nil {
+			t.Errorf("failed on %d: %v", i, err)
+		} else if bytes.Compare(mix, tt.mix) != 0 {
+			t.Errorf("failed on %d: mix mismatch: have %x, want %x", i, mix, tt.mix)
+		} else if bytes.Compare(digest, tt.digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, digest, tt.digest)
+		}
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	hash := make([]byte, 32)
+
+	mix092, _, err := NewProgPoW092().Compute(hash, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mix093, _, err := NewProgPoW093().Compute(hash, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	} else if bytes.Equal(mix092, mix093) {
+		t.Errorf("0.9.2 and 0.9.3 mixes match")
+	}
+
+	if _, _, err := NewProgPoW093().Compute(hash[:31], 0, 0); !errors.Is(err, powerr.ErrInvalidInput) {
+		t.Errorf("expected invalid input error, have %v", err)
+	}
+
+	client := New(newConfig("PROGPOW094", 512), Revision(0))
+	if _, _, err := client.Compute(hash, 0, 0); !errors.Is(err, powerr.ErrUnsupportedVariant) {
+		t.Errorf("expected unsupported variant error, have %v", err)
+	}
+}
+
+func TestComputeBatch(t *testing.T) {
+	hash := testutil.MustDecodeHex("ffeeddccbbaa9988776655443322110000112233445566778899aabbccddeeff")
+	client := NewProgPoW094()
+	client.SetWorkers(3)
+
+	var jobs []ComputeJob
+	for nonce := uint64(0); nonce < 40; nonce++ {
+		jobs = append(jobs, ComputeJob{Hash: hash, Height: (nonce % 2) * 30000, Nonce: nonce})
+	}
+	jobs = append(jobs, ComputeJob{Hash: hash[:31], Height: 0, Nonce: 0})
+
+	results, err := client.ComputeBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, job := range jobs {
+		mix, digest, err := client.Compute(job.Hash, job.Height, job.Nonce)
+		if (err != nil) != (results[i].Err != nil) {
+			t.Errorf("failed on %d: error mismatch: have %v, want %v", i, results[i].Err, err)
+		} else if bytes.Compare(results[i].Mix, mix) != 0 {
+			t.Errorf("failed on %d: mix mismatch: have %x, want %x", i, results[i].Mix, mix)
+		} else if bytes.Compare(results[i].Digest, digest) != 0 {
+			t.Errorf("failed on %d: digest mismatch: have %x, want %x", i, results[i].Digest, digest)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package powkit
+
+import (
+	"github.com/sencha-dev/powkit/internal/dag"
+)
+
+// Storage keeps the caches and datasets of a DAG based hasher, see
+// DAGHasher.SetStorage. Custom storages implement it with the StorageEntry
+// and StorageBuffer types.
+type Storage = dag.Storage
+
+// StorageEntry identifies the data of a cache, L1 cache or dataset.
+type StorageEntry = dag.Entry
+
+// StorageBuffer is the memory holding the data of a StorageEntry.
+type StorageBuffer = dag.Buffer
+
+// NewHeapStorage returns a storage keeping the data on the Go heap, which is
+// generated on every start.
+func NewHeapStorage() Storage {
+	return dag.NewHeapStorage()
+}
+
+// NewFileStorage returns a storage memory mapping files in a directory, the
+// default being ~/.powcache. Processes sharing the directory generate each
+// file once.
+func NewFileStorage(dir string) Storage {
+	return dag.NewFileStorage(dir)
+}
+
+// NewHugePageStorage returns a storage keeping the data in anonymous memory
+// backed by huge pages on Linux, and on the heap elsewhere. The data is
+// generated on every start.
+func NewHugePageStorage() Storage {
+	return dag.NewHugePageStorage()
+}
+
+// NewReadOnlyStorage returns a storage mapping the files pre-generated in a
+// directory, such as caches shipped in a read-only container mount, without
+// ever writing to it. Missing entries are kept in the fallback storage, the
+// heap if nil.
+func NewReadOnlyStorage(dir string, fallback Storage) Storage {
+	return dag.NewReadOnlyStorage(dir, fallback)
+}
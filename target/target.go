@@ -0,0 +1,151 @@
+package target
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+var (
+	// Two256 is 2^256, the numerator for converting between ethash style
+	// difficulties and targets.
+	Two256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+	// Diff1Bitcoin is the difficulty 1 target of Bitcoin derived chains (0x1d00ffff).
+	Diff1Bitcoin = CompactToBig(0x1d00ffff)
+
+	// Diff1Equihash is the difficulty 1 share target used by Equihash (ZCash, Flux) pools.
+	Diff1Equihash = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 243), big.NewInt(1))
+
+	// Diff1Autolykos is the secp256k1 group order, which Ergo divides by the
+	// difficulty to get the b target.
+	Diff1Autolykos, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+)
+
+// DifficultyToTarget returns 2^256 / difficulty, the target used by ethash,
+// kawpow, firopow, octopus and eaglesong. It returns nil for a difficulty
+// that is not positive.
+func DifficultyToTarget(difficulty *big.Int) *big.Int {
+	if difficulty.Sign() <= 0 {
+		return nil
+	}
+
+	return new(big.Int).Div(Two256, difficulty)
+}
+
+// TargetToDifficulty returns 2^256 / target. It returns nil for a target
+// that is not positive.
+func TargetToDifficulty(target *big.Int) *big.Int {
+	if target.Sign() <= 0 {
+		return nil
+	}
+
+	return new(big.Int).Div(Two256, target)
+}
+
+// ShareDifficultyToTarget returns diff1 / difficulty for fractional pool share
+// difficulties, diff1 being the difficulty 1 target of the algorithm (Two256,
+// Diff1Bitcoin, Diff1Equihash, Diff1Autolykos). It returns nil for a difficulty
+// that is not positive.
+func ShareDifficultyToTarget(difficulty float64, diff1 *big.Int) *big.Int {
+	if !(difficulty > 0) {
+		return nil
+	}
+
+	quo := new(big.Float).SetPrec(512).SetInt(diff1)
+	quo.Quo(quo, new(big.Float).SetPrec(512).SetFloat64(difficulty))
+	target, _ := quo.Int(nil)
+
+	return target
+}
+
+// TargetToShareDifficulty returns diff1 / target as a float, the inverse of
+// ShareDifficultyToTarget. It returns zero for a target that is not positive.
+func TargetToShareDifficulty(target, diff1 *big.Int) float64 {
+	if target.Sign() <= 0 {
+		return 0
+	}
+
+	quo := new(big.Float).SetPrec(512).SetInt(diff1)
+	quo.Quo(quo, new(big.Float).SetPrec(512).SetInt(target))
+	difficulty, _ := quo.Float64()
+
+	return difficulty
+}
+
+// MeetsTarget reports whether the big-endian digest is less than or equal to
+// the target. All digests returned by powkit hashers are big-endian (heavyhash
+// digests are already reversed to match Kaspa's block explorers).
+func MeetsTarget(digest []byte, target *big.Int) bool {
+	return new(big.Int).SetBytes(digest).Cmp(target) <= 0
+}
+
+// MeetsTargetLE reports whether the little-endian digest is less than or equal
+// to the target, as used for raw block hashes of Bitcoin derived chains.
+func MeetsTargetLE(digest []byte, target *big.Int) bool {
+	reversed := make([]byte, len(digest))
+	for i := range digest {
+		reversed[len(digest)-1-i] = digest[i]
+	}
+
+	return new(big.Int).SetBytes(reversed).Cmp(target) <= 0
+}
+
+/* autolykos2 */
+
+// AutolykosTarget returns Ergo's b target for the compact nBits of a header,
+// the group order divided by the decoded difficulty. It returns nil if the
+// decoded difficulty is not positive.
+func AutolykosTarget(nBits uint32) *big.Int {
+	difficulty := CompactToBig(nBits)
+	if difficulty.Sign() <= 0 {
+		return nil
+	}
+
+	return new(big.Int).Div(Diff1Autolykos, difficulty)
+}
+
+/* cuckoo */
+
+const (
+	grinBaseEdgeBits = 24
+	grinWeekHeight   = 7 * 24 * 60
+	grinYearHeight   = 52 * grinWeekHeight
+)
+
+// GrinGraphWeight returns the weight of a Cuckatoo graph of the given size,
+// used to scale the difficulty of primary proofs. Cuckatoo31 is linearly
+// phased out starting one year after launch, one edge bit per week.
+func GrinGraphWeight(height, edgeBits uint64) uint64 {
+	xprEdgeBits := edgeBits
+	if edgeBits == 31 && height >= grinYearHeight {
+		decay := 1 + (height-grinYearHeight)/grinWeekHeight
+		if decay > xprEdgeBits {
+			xprEdgeBits = 0
+		} else {
+			xprEdgeBits -= decay
+		}
+	}
+
+	return (2 << (edgeBits - grinBaseEdgeBits)) * xprEdgeBits
+}
+
+// GrinScaledDifficulty returns the difficulty of a proof from the blake2b
+// hash of its packed edges, (scaling << 64) / hash with the hash read as a
+// big-endian uint64. The scaling is the graph weight for primary proofs and
+// the header's secondary scaling for secondary proofs. The result saturates
+// at the maximum uint64.
+func GrinScaledDifficulty(hash []byte, scaling uint64) uint64 {
+	if len(hash) < 8 {
+		return 0
+	}
+
+	value := binary.BigEndian.Uint64(hash)
+	if value == 0 || scaling >= value {
+		return ^uint64(0)
+	}
+
+	difficulty, _ := bits.Div64(scaling, 0, value)
+
+	return difficulty
+}
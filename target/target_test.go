@@ -0,0 +1,345 @@
+package target
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/sencha-dev/powkit/internal/common/testutil"
+)
+
+func mustDecodeBig(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex " + s)
+	}
+
+	return n
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		compact uint32
+		value   *big.Int
+		encoded uint32
+	}{
+		{
+			compact: 0x00000000,
+			value:   big.NewInt(0),
+			encoded: 0x00000000,
+		},
+		{
+			compact: 0x01003456,
+			value:   big.NewInt(0),
+			encoded: 0x00000000,
+		},
+		{
+			compact: 0x01123456,
+			value:   big.NewInt(0x12),
+			encoded: 0x01120000,
+		},
+		{
+			compact: 0x02008000,
+			value:   big.NewInt(0x80),
+			encoded: 0x02008000,
+		},
+		{
+			compact: 0x04923456,
+			value:   big.NewInt(-0x12345600),
+			encoded: 0x04923456,
+		},
+		{
+			compact: 0x04123456,
+			value:   big.NewInt(0x12345600),
+			encoded: 0x04123456,
+		},
+		{
+			compact: 0x1d00ffff,
+			value:   mustDecodeBig("00000000ffff0000000000000000000000000000000000000000000000000000"),
+			encoded: 0x1d00ffff,
+		},
+		{
+			compact: 0x1b0404cb,
+			value:   mustDecodeBig("00000000000404cb000000000000000000000000000000000000000000000000"),
+			encoded: 0x1b0404cb,
+		},
+	}
+
+	for i, tt := range tests {
+		value := CompactToBig(tt.compact)
+		if value.Cmp(tt.value) != 0 {
+			t.Errorf("failed on %d: value mismatch: have %x, want %x", i, value, tt.value)
+		}
+
+		encoded := BigToCompact(tt.value)
+		if encoded != tt.encoded {
+			t.Errorf("failed on %d: compact mismatch: have %08x, want %08x", i, encoded, tt.encoded)
+		}
+	}
+}
+
+func TestDifficultyToTarget(t *testing.T) {
+	tests := []struct {
+		difficulty uint64
+		target     *big.Int
+	}{
+		{
+			difficulty: 2,
+			target:     mustDecodeBig("8000000000000000000000000000000000000000000000000000000000000000"),
+		},
+		{
+			difficulty: 3,
+			target:     mustDecodeBig("5555555555555555555555555555555555555555555555555555555555555555"),
+		},
+		{
+			difficulty: 1 << 32,
+			target:     mustDecodeBig("0000000100000000000000000000000000000000000000000000000000000000"),
+		},
+		{
+			difficulty: 4000000000,
+			target:     mustDecodeBig("0000000112e0be826d694b2e62d01511f12a6061fbaec8bc02357593e70e52ba"),
+		},
+	}
+
+	for i, tt := range tests {
+		target := DifficultyToTarget(new(big.Int).SetUint64(tt.difficulty))
+		if target.Cmp(tt.target) != 0 {
+			t.Errorf("failed on %d: target mismatch: have %x, want %x", i, target, tt.target)
+		}
+
+		fast := DifficultyToTarget256(tt.difficulty)
+		if fast.Big().Cmp(tt.target) != 0 {
+			t.Errorf("failed on %d: fast target mismatch: have %x, want %x", i, fast.Big(), tt.target)
+		}
+
+		difficulty := TargetToDifficulty(target)
+		if difficulty.Uint64() != tt.difficulty {
+			t.Errorf("failed on %d: difficulty mismatch: have %d, want %d", i, difficulty, tt.difficulty)
+		}
+	}
+
+	if DifficultyToTarget256(1).Cmp(&MaxUint256) != 0 {
+		t.Errorf("expected max target for difficulty 1")
+	}
+}
+
+func TestShareDifficultyToTarget(t *testing.T) {
+	tests := []struct {
+		difficulty float64
+		diff1      *big.Int
+		target     *big.Int
+	}{
+		{
+			difficulty: 1,
+			diff1:      Diff1Bitcoin,
+			target:     mustDecodeBig("00000000ffff0000000000000000000000000000000000000000000000000000"),
+		},
+		{
+			difficulty: 0.5,
+			diff1:      Diff1Bitcoin,
+			target:     mustDecodeBig("00000001fffe0000000000000000000000000000000000000000000000000000"),
+		},
+		{
+			difficulty: 16,
+			diff1:      Diff1Equihash,
+			target:     mustDecodeBig("00007fffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		},
+		{
+			difficulty: 1 << 32,
+			diff1:      Two256,
+			target:     mustDecodeBig("0000000100000000000000000000000000000000000000000000000000000000"),
+		},
+	}
+
+	for i, tt := range tests {
+		target := ShareDifficultyToTarget(tt.difficulty, tt.diff1)
+		if target.Cmp(tt.target) != 0 {
+			t.Errorf("failed on %d: target mismatch: have %x, want %x", i, target, tt.target)
+		}
+
+		difficulty := TargetToShareDifficulty(target, tt.diff1)
+		if difficulty != tt.difficulty {
+			t.Errorf("failed on %d: difficulty mismatch: have %f, want %f", i, difficulty, tt.difficulty)
+		}
+	}
+}
+
+func TestMeetsTarget(t *testing.T) {
+	tests := []struct {
+		digest  []byte
+		target  *big.Int
+		meets   bool
+		meetsLE bool
+	}{
+		{
+			digest:  testutil.MustDecodeHex("000000001726686e851f02c584d7cc8a8fbe5938ecdb3ffa2ba16c260ee1fc40"),
+			target:  mustDecodeBig("00000000ffff0000000000000000000000000000000000000000000000000000"),
+			meets:   true,
+			meetsLE: false,
+		},
+		{
+			digest:  testutil.MustDecodeHex("40fce10e266ca12bfa3fdbec3859be8f8acc7d84c5021f856e68261700000000"),
+			target:  mustDecodeBig("00000000ffff0000000000000000000000000000000000000000000000000000"),
+			meets:   false,
+			meetsLE: true,
+		},
+		{
+			digest:  testutil.MustDecodeHex("00000000ffff0000000000000000000000000000000000000000000000000000"),
+			target:  mustDecodeBig("00000000ffff0000000000000000000000000000000000000000000000000000"),
+			meets:   true,
+			meetsLE: true,
+		},
+	}
+
+	for i, tt := range tests {
+		target, _ := Uint256FromBig(tt.target)
+
+		if MeetsTarget(tt.digest, tt.target) != tt.meets {
+			t.Errorf("failed on %d: big-endian mismatch", i)
+		} else if MeetsTarget256(tt.digest, target) != tt.meets {
+			t.Errorf("failed on %d: fast big-endian mismatch", i)
+		} else if MeetsTargetLE(tt.digest, tt.target) != tt.meetsLE {
+			t.Errorf("failed on %d: little-endian mismatch", i)
+		} else if MeetsTarget256LE(tt.digest, target) != tt.meetsLE {
+			t.Errorf("failed on %d: fast little-endian mismatch", i)
+		}
+	}
+}
+
+func TestUint256SetBytes(t *testing.T) {
+	tests := []struct {
+		buf []byte
+		be  Uint256
+		le  Uint256
+	}{
+		{
+			buf: testutil.MustDecodeHex("01"),
+			be:  Uint256{1, 0, 0, 0},
+			le:  Uint256{1, 0, 0, 0},
+		},
+		{
+			buf: testutil.MustDecodeHex("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"),
+			be:  Uint256{0x191a1b1c1d1e1f20, 0x1112131415161718, 0x090a0b0c0d0e0f10, 0x0102030405060708},
+			le:  Uint256{0x0807060504030201, 0x100f0e0d0c0b0a09, 0x1817161514131211, 0x201f1e1d1c1b1a19},
+		},
+		{
+			// only the low 32 bytes are kept, the first byte for big-endian
+			// and the last one for little-endian are dropped
+			buf: testutil.MustDecodeHex("ff0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"),
+			be:  Uint256{0x191a1b1c1d1e1f20, 0x1112131415161718, 0x090a0b0c0d0e0f10, 0x0102030405060708},
+			le:  Uint256{0x07060504030201ff, 0x0f0e0d0c0b0a0908, 0x1716151413121110, 0x1f1e1d1c1b1a1918},
+		},
+	}
+
+	for i, tt := range tests {
+		if z := new(Uint256).SetBytes(tt.buf); *z != tt.be {
+			t.Errorf("failed on %d: big-endian mismatch: have %x, want %x", i, *z, tt.be)
+		} else if z := new(Uint256).SetBytesLE(tt.buf); *z != tt.le {
+			t.Errorf("failed on %d: little-endian mismatch: have %x, want %x", i, *z, tt.le)
+		}
+	}
+}
+
+func TestAutolykosTarget(t *testing.T) {
+	tests := []struct {
+		nBits  uint32
+		target *big.Int
+	}{
+		{
+			nBits:  0x01010000,
+			target: Diff1Autolykos,
+		},
+		{
+			nBits:  0x03100000,
+			target: mustDecodeBig("00000fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd03"),
+		},
+	}
+
+	for i, tt := range tests {
+		target := AutolykosTarget(tt.nBits)
+		if target.Cmp(tt.target) != 0 {
+			t.Errorf("failed on %d: target mismatch: have %x, want %x", i, target, tt.target)
+		}
+	}
+}
+
+func TestGrinGraphWeight(t *testing.T) {
+	tests := []struct {
+		height   uint64
+		edgeBits uint64
+		weight   uint64
+	}{
+		{
+			height:   0,
+			edgeBits: 29,
+			weight:   1856,
+		},
+		{
+			height:   0,
+			edgeBits: 31,
+			weight:   7936,
+		},
+		{
+			height:   0,
+			edgeBits: 32,
+			weight:   16384,
+		},
+		{
+			height:   grinYearHeight,
+			edgeBits: 31,
+			weight:   7680,
+		},
+		{
+			height:   grinYearHeight + grinWeekHeight,
+			edgeBits: 31,
+			weight:   7424,
+		},
+		{
+			height:   grinYearHeight + 30*grinWeekHeight,
+			edgeBits: 31,
+			weight:   0,
+		},
+		{
+			height:   grinYearHeight + 30*grinWeekHeight,
+			edgeBits: 32,
+			weight:   16384,
+		},
+	}
+
+	for i, tt := range tests {
+		weight := GrinGraphWeight(tt.height, tt.edgeBits)
+		if weight != tt.weight {
+			t.Errorf("failed on %d: weight mismatch: have %d, want %d", i, weight, tt.weight)
+		}
+	}
+}
+
+func TestGrinScaledDifficulty(t *testing.T) {
+	tests := []struct {
+		hash       []byte
+		scaling    uint64
+		difficulty uint64
+	}{
+		{
+			hash:       testutil.MustDecodeHex("8000000000000000"),
+			scaling:    1,
+			difficulty: 2,
+		},
+		{
+			hash:       testutil.MustDecodeHex("0000000100000000"),
+			scaling:    1856,
+			difficulty: 1856 << 32,
+		},
+		{
+			hash:       testutil.MustDecodeHex("0000000000000001"),
+			scaling:    1856,
+			difficulty: ^uint64(0),
+		},
+	}
+
+	for i, tt := range tests {
+		difficulty := GrinScaledDifficulty(tt.hash, tt.scaling)
+		if difficulty != tt.difficulty {
+			t.Errorf("failed on %d: difficulty mismatch: have %d, want %d", i, difficulty, tt.difficulty)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package target
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// Uint256 is a fixed width 256 bit unsigned integer, stored as four 64 bit
+// limbs with the least significant limb first. It avoids the allocations of
+// big.Int for the hot paths of share validation.
+type Uint256 [4]uint64
+
+// MaxUint256 is 2^256 - 1, the largest possible target.
+var MaxUint256 = Uint256{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+
+func NewUint256(x uint64) *Uint256 {
+	return &Uint256{x, 0, 0, 0}
+}
+
+// Uint256FromBig converts a big integer to a Uint256. The second return
+// value is false if the value is negative or does not fit in 256 bits.
+func Uint256FromBig(x *big.Int) (*Uint256, bool) {
+	if x.Sign() < 0 || x.BitLen() > 256 {
+		return nil, false
+	}
+
+	z := new(Uint256)
+	z.SetBytes(x.Bytes())
+
+	return z, true
+}
+
+// SetBytes interprets buf as a big-endian integer. Like SetBytesLE, only the
+// low 32 bytes of longer input are kept.
+func (z *Uint256) SetBytes(buf []byte) *Uint256 {
+	if len(buf) > 32 {
+		buf = buf[len(buf)-32:]
+	}
+
+	var padded [32]byte
+	copy(padded[32-len(buf):], buf)
+
+	for i := range z {
+		z[i] = binary.BigEndian.Uint64(padded[24-i*8:])
+	}
+
+	return z
+}
+
+// SetBytesLE interprets buf as a little-endian integer. Only the low 32 bytes
+// of longer input are kept.
+func (z *Uint256) SetBytesLE(buf []byte) *Uint256 {
+	var padded [32]byte
+	copy(padded[:], buf)
+
+	for i := range z {
+		z[i] = binary.LittleEndian.Uint64(padded[i*8:])
+	}
+
+	return z
+}
+
+// Bytes32 returns the value as a 32 byte big-endian array.
+func (z *Uint256) Bytes32() [32]byte {
+	var buf [32]byte
+	for i := range z {
+		binary.BigEndian.PutUint64(buf[24-i*8:], z[i])
+	}
+
+	return buf
+}
+
+// Big returns the value as a big integer.
+func (z *Uint256) Big() *big.Int {
+	buf := z.Bytes32()
+
+	return new(big.Int).SetBytes(buf[:])
+}
+
+func (z *Uint256) IsZero() bool {
+	return z[0]|z[1]|z[2]|z[3] == 0
+}
+
+// Cmp compares z and x and returns -1, 0 or +1.
+func (z *Uint256) Cmp(x *Uint256) int {
+	for i := len(z) - 1; i >= 0; i-- {
+		if z[i] < x[i] {
+			return -1
+		} else if z[i] > x[i] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// DivUint64 sets z to the quotient x/d and returns the remainder.
+// It panics if d is zero.
+func (z *Uint256) DivUint64(x *Uint256, d uint64) uint64 {
+	var rem uint64
+	for i := len(x) - 1; i >= 0; i-- {
+		z[i], rem = bits.Div64(rem, x[i], d)
+	}
+
+	return rem
+}
+
+// DifficultyToTarget256 is the fixed width equivalent of DifficultyToTarget.
+// A difficulty of zero or one returns MaxUint256, since 2^256 does not fit.
+func DifficultyToTarget256(difficulty uint64) *Uint256 {
+	z := new(Uint256)
+	if difficulty < 2 {
+		*z = MaxUint256
+		return z
+	}
+
+	// floor(2^256 / d) is floor((2^256 - 1) / d), plus one if
+	// d evenly divides 2^256 (the remainder is then d - 1)
+	rem := z.DivUint64(&MaxUint256, difficulty)
+	if rem == difficulty-1 {
+		var carry uint64
+		z[0], carry = bits.Add64(z[0], 1, 0)
+		z[1], carry = bits.Add64(z[1], 0, carry)
+		z[2], carry = bits.Add64(z[2], 0, carry)
+		z[3], _ = bits.Add64(z[3], 0, carry)
+	}
+
+	return z
+}
+
+// MeetsTarget256 is the fixed width equivalent of MeetsTarget.
+func MeetsTarget256(digest []byte, target *Uint256) bool {
+	if len(digest) > 32 {
+		return false
+	}
+
+	return new(Uint256).SetBytes(digest).Cmp(target) <= 0
+}
+
+// MeetsTarget256LE is the fixed width equivalent of MeetsTargetLE.
+func MeetsTarget256LE(digest []byte, target *Uint256) bool {
+	if len(digest) > 32 {
+		return false
+	}
+
+	return new(Uint256).SetBytesLE(digest).Cmp(target) <= 0
+}